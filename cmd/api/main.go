@@ -9,6 +9,7 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata"
 
 	"github.com/turbekoff/todo/internal/config"
 	"github.com/turbekoff/todo/internal/delivery/rest"
@@ -44,6 +45,46 @@ func Error(err error) slog.Attr {
 	}
 }
 
+func purgeTrash(ctx context.Context, log *slog.Logger, taskService service.TaskService, cfg *config.TrashConfig) {
+	ticker := time.NewTicker(cfg.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		count, err := taskService.PurgeTrash(cfg.Retention)
+		if err != nil {
+			log.Error("failed to purge trash", Error(err))
+		} else if count > 0 {
+			log.Info(fmt.Sprintf("Purged %d tasks from trash", count))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func autoArchive(ctx context.Context, log *slog.Logger, taskService service.TaskService, cfg *config.ArchiveConfig) {
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		count, err := taskService.AutoArchive()
+		if err != nil {
+			log.Error("failed to archive tasks", Error(err))
+		} else if count > 0 {
+			log.Info(fmt.Sprintf("Archived %d tasks", count))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 func main() {
 	cfg, err := config.Load()
 	log := setupLogger(cfg.DebugMode)
@@ -60,16 +101,31 @@ func main() {
 	}
 
 	database := client.Database(cfg.Mongo.Database)
+	if err := mongo.CreateIndexes(database); err != nil {
+		log.Error("failed to create database indexes", Error(err))
+		return
+	}
+
 	userRepository := mongo.NewUserRepositry(database)
 	taskRepository := mongo.NewTaskRepository(database)
 	sessionRepository := mongo.NewSessionRepository(database)
+	projectRepository := mongo.NewProjectRepository(database)
+	timeEntryRepository := mongo.NewTimeEntryRepository(database)
+	templateRepository := mongo.NewTemplateRepository(database)
+	filterRepository := mongo.NewFilterRepository(database)
+	commentRepository := mongo.NewCommentRepository(database)
 	hasher := hash.NewArgon2idHasher(cfg.PasswordPepper)
 
-	userService := service.NewUserService(hasher, userRepository, taskRepository, sessionRepository)
-	taskService := service.NewTaskService(userRepository, taskRepository)
+	userService := service.NewUserService(hasher, userRepository, taskRepository, sessionRepository, projectRepository, timeEntryRepository, templateRepository, filterRepository, commentRepository)
+	taskService := service.NewTaskService(userRepository, taskRepository, projectRepository, timeEntryRepository, commentRepository)
 	sessionService := service.NewSessionService(hasher, userRepository, sessionRepository, &cfg.JWT)
+	projectService := service.NewProjectService(userRepository, taskRepository, projectRepository)
+	timeService := service.NewTimeService(taskRepository, timeEntryRepository)
+	templateService := service.NewTemplateService(taskService, userRepository, templateRepository)
+	filterService := service.NewFilterService(userRepository, taskRepository, filterRepository)
+	commentService := service.NewCommentService(taskRepository, commentRepository)
 
-	router := rest.NewRouter(log, userService, taskService, sessionService)
+	router := rest.NewRouter(log, userService, taskService, sessionService, projectService, timeService, templateService, filterService, commentService)
 	server := server.New(router, &cfg.HTTP)
 
 	go func() {
@@ -80,9 +136,16 @@ func main() {
 
 	log.Info(fmt.Sprintf("Starting HTTP server on %s:%d", cfg.HTTP.Host, cfg.HTTP.Port))
 
+	jobs, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	go purgeTrash(jobs, log, taskService, &cfg.Trash)
+	go autoArchive(jobs, log, taskService, &cfg.Archive)
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM, syscall.SIGINT, syscall.SIGTSTP)
 	<-quit
+	stopJobs()
 
 	ctx, close := context.WithTimeout(context.Background(), 10*time.Second)
 	defer close()
@@ -30,12 +30,23 @@ type MongoConfig struct {
 	Database string `env:"DATABASE"`
 }
 
+type TrashConfig struct {
+	Retention     time.Duration `env:"RETENTION" env-default:"720h"`
+	PurgeInterval time.Duration `env:"PURGE_INTERVAL" env-default:"1h"`
+}
+
+type ArchiveConfig struct {
+	Interval time.Duration `env:"INTERVAL" env-default:"1h"`
+}
+
 type Config struct {
-	DebugMode      Mode        `env:"DEBUG_MODE" env-default:"development"`
-	PasswordPepper string      `env:"PASSWORD_PEPPER" env-required:"true"`
-	JWT            JWTConfig   `env-prefix:"JWT_"`
-	HTTP           HTTPConfig  `env-prefix:"HTTP_"`
-	Mongo          MongoConfig `env-prefix:"MONGO_"`
+	DebugMode      Mode          `env:"DEBUG_MODE" env-default:"development"`
+	PasswordPepper string        `env:"PASSWORD_PEPPER" env-required:"true"`
+	JWT            JWTConfig     `env-prefix:"JWT_"`
+	HTTP           HTTPConfig    `env-prefix:"HTTP_"`
+	Mongo          MongoConfig   `env-prefix:"MONGO_"`
+	Trash          TrashConfig   `env-prefix:"TRASH_"`
+	Archive        ArchiveConfig `env-prefix:"ARCHIVE_"`
 }
 
 func loadPath() (string, error) {
@@ -51,6 +62,21 @@ func loadPath() (string, error) {
 	return filepath.Join(root, path), nil
 }
 
+func (c *Config) validate() error {
+	if c.Trash.Retention <= 0 {
+		return errors.New("trash retention must be positive")
+	}
+
+	if c.Trash.PurgeInterval <= 0 {
+		return errors.New("trash purge interval must be positive")
+	}
+
+	if c.Archive.Interval <= 0 {
+		return errors.New("archive interval must be positive")
+	}
+	return nil
+}
+
 func Load() (Config, error) {
 	path, err := loadPath()
 	if err != nil {
@@ -67,5 +93,9 @@ func Load() (Config, error) {
 	if err != nil {
 		return Config{DebugMode: M_NULL}, err
 	}
+
+	if err := config.validate(); err != nil {
+		return Config{DebugMode: M_NULL}, err
+	}
 	return config, nil
 }
@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/render"
+)
+
+type CommentRequest struct {
+	Body string `json:"body"`
+}
+
+func (comment *CommentRequest) Bind(r *http.Request) error {
+	return nil
+}
+
+type CommentResponce struct {
+	ID        string     `json:"id"`
+	Task      string     `json:"task"`
+	Author    string     `json:"author"`
+	Body      string     `json:"body"`
+	CreatedAt time.Time  `json:"createdAt"`
+	EditedAt  *time.Time `json:"editedAt,omitempty"`
+}
+
+func (comment *CommentResponce) Render(w http.ResponseWriter, r *http.Request) error {
+	render.Status(r, http.StatusOK)
+	return nil
+}
+
+type CommentListResponce []CommentResponce
+
+func (comments *CommentListResponce) Render(w http.ResponseWriter, r *http.Request) error {
+	render.Status(r, http.StatusOK)
+	return nil
+}
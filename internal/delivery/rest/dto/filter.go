@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/render"
+)
+
+type FilterRequest struct {
+	Name     string `json:"name"`
+	Query    string `json:"query"`
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+func (filter *FilterRequest) Bind(r *http.Request) error {
+	return nil
+}
+
+type FilterResponce struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Query     string    `json:"query"`
+	TimeZone  string    `json:"timeZone,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func (filter *FilterResponce) Render(w http.ResponseWriter, r *http.Request) error {
+	render.Status(r, http.StatusOK)
+	return nil
+}
+
+type FilterListResponce []FilterResponce
+
+func (filters *FilterListResponce) Render(w http.ResponseWriter, r *http.Request) error {
+	render.Status(r, http.StatusOK)
+	return nil
+}
@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/render"
+)
+
+type ProjectRequest struct {
+	Name     string `json:"name"`
+	Color    string `json:"color"`
+	Position *int   `json:"position"`
+	Archived bool   `json:"archived"`
+}
+
+func (project *ProjectRequest) Bind(r *http.Request) error {
+	return nil
+}
+
+type ProjectResponce struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"`
+	Position  int       `json:"position"`
+	Archived  bool      `json:"archived"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func (project *ProjectResponce) Render(w http.ResponseWriter, r *http.Request) error {
+	render.Status(r, http.StatusOK)
+	return nil
+}
+
+type ProjectListResponce []ProjectResponce
+
+func (projects *ProjectListResponce) Render(w http.ResponseWriter, r *http.Request) error {
+	render.Status(r, http.StatusOK)
+	return nil
+}
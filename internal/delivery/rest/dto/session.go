@@ -1,6 +1,7 @@
 package dto
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
@@ -16,6 +17,17 @@ func (session *CreateSessionRequest) Bind(r *http.Request) error {
 	return nil
 }
 
+type RefreshSessionRequest struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+func (session *RefreshSessionRequest) Bind(r *http.Request) error {
+	if session.RefreshToken == "" {
+		return errors.New("refresh token not specified")
+	}
+	return nil
+}
+
 type SessionResponce struct {
 	AccessToken  string    `json:"accessToken"`
 	RefreshToken string    `json:"refreshToken"`
@@ -26,3 +38,25 @@ func (session *SessionResponce) Render(w http.ResponseWriter, r *http.Request) e
 	render.Status(r, http.StatusOK)
 	return nil
 }
+
+type SessionInfoResponce struct {
+	ID         string    `json:"id"`
+	Device     string    `json:"device"`
+	UserAgent  string    `json:"userAgent"`
+	Current    bool      `json:"current"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+	ExpireAt   time.Time `json:"expireAt"`
+}
+
+func (session *SessionInfoResponce) Render(w http.ResponseWriter, r *http.Request) error {
+	render.Status(r, http.StatusOK)
+	return nil
+}
+
+type SessionListResponce []SessionInfoResponce
+
+func (sessions *SessionListResponce) Render(w http.ResponseWriter, r *http.Request) error {
+	render.Status(r, http.StatusOK)
+	return nil
+}
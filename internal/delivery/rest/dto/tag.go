@@ -0,0 +1,19 @@
+package dto
+
+import (
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+type TagResponce struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+type TagListResponce []TagResponce
+
+func (tags *TagListResponce) Render(w http.ResponseWriter, r *http.Request) error {
+	render.Status(r, http.StatusOK)
+	return nil
+}
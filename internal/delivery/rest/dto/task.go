@@ -8,20 +8,75 @@ import (
 )
 
 type TaskRequest struct {
-	Name      string `json:"name"`
-	Completed bool   `json:"completed"`
+	Project   string   `json:"project,omitempty"`
+	Parent    string   `json:"parent,omitempty"`
+	Name      string   `json:"name"`
+	Notes     string   `json:"notes,omitempty"`
+	Status    string   `json:"status,omitempty"`
+	Completed bool     `json:"completed"`
+	Priority  string   `json:"priority,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+	DueDate   string   `json:"dueDate,omitempty"`
+	DueTime   string   `json:"dueTime,omitempty"`
+	TimeZone  string   `json:"timeZone,omitempty"`
+
+	Recurrence          string `json:"recurrence,omitempty"`
+	RecurFromCompletion bool   `json:"recurFromCompletion,omitempty"`
+
+	CompleteSubtasks bool `json:"completeSubtasks,omitempty"`
+
+	BlockedBy []string `json:"blockedBy,omitempty"`
 }
 
 func (task *TaskRequest) Bind(r *http.Request) error {
 	return nil
 }
 
+type QuickTaskRequest struct {
+	Text     string `json:"text"`
+	Project  string `json:"project,omitempty"`
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+func (task *QuickTaskRequest) Bind(r *http.Request) error {
+	return nil
+}
+
 type TaskResponce struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Completed bool      `json:"completed"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	ID          string     `json:"id"`
+	Project     string     `json:"project,omitempty"`
+	Parent      string     `json:"parent,omitempty"`
+	Name        string     `json:"name"`
+	Notes       string     `json:"notes,omitempty"`
+	NotesHTML   string     `json:"notesHtml,omitempty"`
+	Status      string     `json:"status"`
+	Completed   bool       `json:"completed"`
+	Archived    bool       `json:"archived"`
+	Priority    string     `json:"priority"`
+	Tags        []string   `json:"tags"`
+	DueDate     string     `json:"dueDate,omitempty"`
+	DueTime     string     `json:"dueTime,omitempty"`
+	TimeZone    string     `json:"timeZone,omitempty"`
+	DueAt       *time.Time `json:"dueAt,omitempty"`
+	Position    string     `json:"position,omitempty"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+	DeletedAt   *time.Time `json:"deletedAt,omitempty"`
+
+	Recurrence          string `json:"recurrence,omitempty"`
+	RecurFromCompletion bool   `json:"recurFromCompletion,omitempty"`
+
+	BlockedBy []string `json:"blockedBy,omitempty"`
+	Blocked   bool     `json:"blocked"`
+
+	Subtasks []TaskResponce    `json:"subtasks,omitempty"`
+	Progress *ProgressResponce `json:"progress,omitempty"`
+}
+
+type ProgressResponce struct {
+	Completed int `json:"completed"`
+	Total     int `json:"total"`
 }
 
 func (task *TaskResponce) Render(w http.ResponseWriter, r *http.Request) error {
@@ -35,3 +90,87 @@ func (tasks *TaskListResponce) Render(w http.ResponseWriter, r *http.Request) er
 	render.Status(r, http.StatusOK)
 	return nil
 }
+
+type TaskPageResponce struct {
+	Tasks      TaskListResponce `json:"tasks"`
+	NextCursor string           `json:"nextCursor,omitempty"`
+	Total      *int64           `json:"total,omitempty"`
+}
+
+func (page *TaskPageResponce) Render(w http.ResponseWriter, r *http.Request) error {
+	render.Status(r, http.StatusOK)
+	return nil
+}
+
+type TaskDependencyResponce struct {
+	Task      string `json:"task"`
+	BlockedBy string `json:"blockedBy"`
+}
+
+// Edges point from a task to a task blocking it.
+type TaskGraphResponce struct {
+	Tasks TaskListResponce         `json:"tasks"`
+	Edges []TaskDependencyResponce `json:"edges"`
+}
+
+func (graph *TaskGraphResponce) Render(w http.ResponseWriter, r *http.Request) error {
+	render.Status(r, http.StatusOK)
+	return nil
+}
+
+type MoveTaskRequest struct {
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+func (move *MoveTaskRequest) Bind(r *http.Request) error {
+	return nil
+}
+
+type ArchiveRequest struct {
+	OlderThanDays int `json:"olderThanDays"`
+}
+
+func (archive *ArchiveRequest) Bind(r *http.Request) error {
+	return nil
+}
+
+type ArchiveResponce struct {
+	Archived int64 `json:"archived"`
+}
+
+func (archive *ArchiveResponce) Render(w http.ResponseWriter, r *http.Request) error {
+	render.Status(r, http.StatusOK)
+	return nil
+}
+
+type TaskOperationRequest struct {
+	Op      string   `json:"op"`
+	IDs     []string `json:"ids"`
+	Project string   `json:"project,omitempty"`
+	Tag     string   `json:"tag,omitempty"`
+}
+
+type BatchRequest struct {
+	Operations []TaskOperationRequest `json:"operations"`
+}
+
+func (batch *BatchRequest) Bind(r *http.Request) error {
+	return nil
+}
+
+type TaskOperationResponce struct {
+	ID    string `json:"id"`
+	Op    string `json:"op"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+type BatchResponce struct {
+	Results []TaskOperationResponce `json:"results"`
+}
+
+func (batch *BatchResponce) Render(w http.ResponseWriter, r *http.Request) error {
+	render.Status(r, http.StatusOK)
+	return nil
+}
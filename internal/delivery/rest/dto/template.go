@@ -0,0 +1,68 @@
+package dto
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/render"
+)
+
+type TemplateTaskRequest struct {
+	Name      string                `json:"name"`
+	Notes     string                `json:"notes"`
+	Priority  string                `json:"priority"`
+	Tags      []string              `json:"tags"`
+	DueOffset *int                  `json:"dueOffset"`
+	DueTime   string                `json:"dueTime"`
+	Subtasks  []TemplateTaskRequest `json:"subtasks"`
+}
+
+type TemplateRequest struct {
+	Name  string                `json:"name"`
+	Tasks []TemplateTaskRequest `json:"tasks"`
+}
+
+func (template *TemplateRequest) Bind(r *http.Request) error {
+	return nil
+}
+
+type InstantiateTemplateRequest struct {
+	Project  string            `json:"project"`
+	Date     string            `json:"date"`
+	TimeZone string            `json:"timeZone"`
+	Values   map[string]string `json:"values"`
+}
+
+func (instance *InstantiateTemplateRequest) Bind(r *http.Request) error {
+	return nil
+}
+
+type TemplateTaskResponce struct {
+	Name      string                 `json:"name"`
+	Notes     string                 `json:"notes,omitempty"`
+	Priority  string                 `json:"priority"`
+	Tags      []string               `json:"tags,omitempty"`
+	DueOffset *int                   `json:"dueOffset,omitempty"`
+	DueTime   string                 `json:"dueTime,omitempty"`
+	Subtasks  []TemplateTaskResponce `json:"subtasks,omitempty"`
+}
+
+type TemplateResponce struct {
+	ID        string                 `json:"id"`
+	Name      string                 `json:"name"`
+	Tasks     []TemplateTaskResponce `json:"tasks"`
+	CreatedAt time.Time              `json:"createdAt"`
+	UpdatedAt time.Time              `json:"updatedAt"`
+}
+
+func (template *TemplateResponce) Render(w http.ResponseWriter, r *http.Request) error {
+	render.Status(r, http.StatusOK)
+	return nil
+}
+
+type TemplateListResponce []TemplateResponce
+
+func (templates *TemplateListResponce) Render(w http.ResponseWriter, r *http.Request) error {
+	render.Status(r, http.StatusOK)
+	return nil
+}
@@ -0,0 +1,71 @@
+package dto
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/render"
+)
+
+type TimeEntryRequest struct {
+	Start time.Time  `json:"start"`
+	End   *time.Time `json:"end"`
+	Note  string     `json:"note,omitempty"`
+}
+
+func (entry *TimeEntryRequest) Bind(r *http.Request) error {
+	return nil
+}
+
+type TimerRequest struct {
+	Note string `json:"note,omitempty"`
+}
+
+func (timer *TimerRequest) Bind(r *http.Request) error {
+	return nil
+}
+
+// TimeEntryResponce counts the seconds of a running entry until now.
+type TimeEntryResponce struct {
+	ID        string     `json:"id"`
+	Task      string     `json:"task"`
+	Start     time.Time  `json:"start"`
+	End       *time.Time `json:"end,omitempty"`
+	Running   bool       `json:"running"`
+	Seconds   int64      `json:"seconds"`
+	Note      string     `json:"note,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+}
+
+func (entry *TimeEntryResponce) Render(w http.ResponseWriter, r *http.Request) error {
+	render.Status(r, http.StatusOK)
+	return nil
+}
+
+type TimeEntryListResponce []TimeEntryResponce
+
+func (entries *TimeEntryListResponce) Render(w http.ResponseWriter, r *http.Request) error {
+	render.Status(r, http.StatusOK)
+	return nil
+}
+
+type TimeTotalResponce struct {
+	Key     string `json:"key"`
+	Seconds int64  `json:"seconds"`
+}
+
+type TimeReportResponce struct {
+	From     string              `json:"from"`
+	To       string              `json:"to"`
+	TimeZone string              `json:"timeZone"`
+	Seconds  int64               `json:"seconds"`
+	Days     []TimeTotalResponce `json:"days"`
+	Tags     []TimeTotalResponce `json:"tags"`
+	Projects []TimeTotalResponce `json:"projects"`
+}
+
+func (report *TimeReportResponce) Render(w http.ResponseWriter, r *http.Request) error {
+	render.Status(r, http.StatusOK)
+	return nil
+}
@@ -16,10 +16,24 @@ func (user *UserRequest) Bind(r *http.Request) error {
 	return nil
 }
 
+type UserPatchRequest struct {
+	Name     string  `json:"name"`
+	Password *string `json:"password,omitempty"`
+}
+
+type AutoArchiveRequest struct {
+	AutoArchiveDays int `json:"autoArchiveDays"`
+}
+
+func (archive *AutoArchiveRequest) Bind(r *http.Request) error {
+	return nil
+}
+
 type UserResponce struct {
-	Name      string    `json:"name"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	Name            string    `json:"name"`
+	AutoArchiveDays int       `json:"autoArchiveDays"`
+	CreatedAt       time.Time `json:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
 }
 
 func (user *UserResponce) Render(w http.ResponseWriter, r *http.Request) error {
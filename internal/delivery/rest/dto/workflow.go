@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+type WorkflowStatusRequest struct {
+	Key  string   `json:"key"`
+	Name string   `json:"name"`
+	Done bool     `json:"done"`
+	Next []string `json:"next,omitempty"`
+}
+
+// No statuses bring back the default workflow.
+type WorkflowRequest struct {
+	Statuses []WorkflowStatusRequest `json:"statuses"`
+}
+
+func (workflow *WorkflowRequest) Bind(r *http.Request) error {
+	return nil
+}
+
+type WorkflowStatusResponce struct {
+	Key  string   `json:"key"`
+	Name string   `json:"name"`
+	Done bool     `json:"done"`
+	Next []string `json:"next"`
+}
+
+type WorkflowResponce struct {
+	Statuses []WorkflowStatusResponce `json:"statuses"`
+}
+
+func (workflow *WorkflowResponce) Render(w http.ResponseWriter, r *http.Request) error {
+	render.Status(r, http.StatusOK)
+	return nil
+}
+
+type BoardColumnResponce struct {
+	Status WorkflowStatusResponce `json:"status"`
+	Tasks  TaskListResponce       `json:"tasks"`
+	Total  int                    `json:"total"`
+}
+
+type BoardResponce struct {
+	Columns []BoardColumnResponce `json:"columns"`
+}
+
+func (board *BoardResponce) Render(w http.ResponseWriter, r *http.Request) error {
+	render.Status(r, http.StatusOK)
+	return nil
+}
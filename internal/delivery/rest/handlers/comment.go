@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"github.com/turbekoff/todo/internal/delivery/rest/dto"
+	"github.com/turbekoff/todo/internal/domain/entities"
+	"github.com/turbekoff/todo/internal/domain/repositories"
+	"github.com/turbekoff/todo/internal/service"
+	"golang.org/x/exp/slog"
+)
+
+var ErrCommentAuthorization = errors.New("you don't have authorization to manage this comment")
+
+func commentResponce(comment *entities.Comment) *dto.CommentResponce {
+	return &dto.CommentResponce{
+		ID:        comment.ID,
+		Task:      comment.Task,
+		Author:    comment.Author,
+		Body:      comment.Body,
+		CreatedAt: comment.CreatedAt,
+		EditedAt:  comment.EditedAt,
+	}
+}
+
+func readComment(r *http.Request, commentService service.CommentService) (*entities.Comment, int, error) {
+	comment, err := commentService.Read(chi.URLParam(r, "comment"))
+	if errors.Is(err, repositories.ErrCommentNotFound) {
+		return nil, http.StatusNotFound, err
+	}
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	if comment.Author != fmt.Sprint(r.Context().Value("auth.id")) {
+		return nil, http.StatusForbidden, ErrCommentAuthorization
+	}
+
+	if comment.Task != chi.URLParam(r, "id") {
+		return nil, http.StatusNotFound, repositories.ErrCommentNotFound
+	}
+	return comment, http.StatusOK, nil
+}
+
+func NewReadComments(log *slog.Logger, taskService service.TaskService, commentService service.CommentService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "readComments"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		task, err := taskService.Read(chi.URLParam(r, "id"))
+		if err != nil {
+			log.Error("failed to read task", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusInternalServerError, Err: err.Error()})
+			return
+		}
+
+		if task.Owner != fmt.Sprint(r.Context().Value("auth.id")) {
+			log.Error("failed to read task", slog.Attr{Key: "error", Value: slog.StringValue(ErrTaskAuthorization.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusForbidden, Err: ErrTaskAuthorization.Error()})
+			return
+		}
+
+		comments, err := commentService.ReadAllByTask(task.ID)
+		if err != nil {
+			log.Error("failed to read comments", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusInternalServerError, Err: err.Error()})
+			return
+		}
+
+		result := dto.CommentListResponce{}
+		for _, comment := range comments {
+			result = append(result, *commentResponce(comment))
+		}
+
+		render.Render(w, r, &result)
+	}
+}
+
+func NewCreateComment(log *slog.Logger, commentService service.CommentService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "createComment"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		bind := &dto.CommentRequest{}
+		if err := render.Bind(r, bind); err != nil {
+			log.Error("failed to load request", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		comment, err := commentService.Create(chi.URLParam(r, "id"), fmt.Sprint(r.Context().Value("auth.id")), bind.Body)
+		if errors.Is(err, repositories.ErrTaskNotFound) {
+			log.Error("failed to create comment", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusNotFound, Err: err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrCommentAuthorization) {
+			log.Error("failed to create comment", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusForbidden, Err: err.Error()})
+			return
+		}
+		if err != nil {
+			log.Error("failed to create comment", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, commentResponce(comment))
+	}
+}
+
+func NewUpdateComment(log *slog.Logger, commentService service.CommentService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "updateComment"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		comment, code, err := readComment(r, commentService)
+		if err != nil {
+			log.Error("failed to read comment", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: code, Err: err.Error()})
+			return
+		}
+
+		bind := &dto.CommentRequest{}
+		if err := render.Bind(r, bind); err != nil {
+			log.Error("failed to load request", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		comment, err = commentService.Update(comment.ID, bind.Body)
+		if err != nil {
+			log.Error("failed to update comment", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		render.Render(w, r, commentResponce(comment))
+	}
+}
+
+func NewDeleteComment(log *slog.Logger, commentService service.CommentService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "deleteComment"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		comment, code, err := readComment(r, commentService)
+		if err != nil {
+			log.Error("failed to read comment", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: code, Err: err.Error()})
+			return
+		}
+
+		if err := commentService.Delete(comment.ID); err != nil {
+			log.Error("failed to delete comment", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusInternalServerError, Err: err.Error()})
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+	}
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"github.com/turbekoff/todo/internal/delivery/rest/dto"
+	"github.com/turbekoff/todo/internal/domain/repositories"
+	"github.com/turbekoff/todo/internal/service"
+	"golang.org/x/exp/slog"
+)
+
+func NewReadDependencies(log *slog.Logger, taskService service.TaskService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "readDependencies"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		task, err := taskService.Read(chi.URLParam(r, "id"))
+		if errors.Is(err, repositories.ErrTaskNotFound) {
+			log.Error("failed to read task", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusNotFound, Err: err.Error()})
+			return
+		}
+		if err != nil {
+			log.Error("failed to read task", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusInternalServerError, Err: err.Error()})
+			return
+		}
+
+		if task.Owner != fmt.Sprint(r.Context().Value("auth.id")) {
+			log.Error("failed to read task", slog.Attr{Key: "error", Value: slog.StringValue(ErrTaskAuthorization.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusForbidden, Err: ErrTaskAuthorization.Error()})
+			return
+		}
+
+		graph, err := taskService.ReadDependencies(task.ID)
+		if errors.Is(err, service.ErrTaskTrashed) {
+			log.Error("failed to read dependencies", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusNotFound, Err: err.Error()})
+			return
+		}
+		if err != nil {
+			log.Error("failed to read dependencies", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusInternalServerError, Err: err.Error()})
+			return
+		}
+
+		result := &dto.TaskGraphResponce{Tasks: dto.TaskListResponce{}, Edges: []dto.TaskDependencyResponce{}}
+		for _, t := range graph.Tasks {
+			result.Tasks = append(result.Tasks, *taskResponce(t))
+		}
+
+		for _, edge := range graph.Edges {
+			result.Edges = append(result.Edges, dto.TaskDependencyResponce{Task: edge.Task, BlockedBy: edge.BlockedBy})
+		}
+
+		render.Render(w, r, result)
+	}
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/turbekoff/todo/internal/domain/entities"
+)
+
+func etag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// Subtask changes don't bump the version of the task, so the tag carries a
+// digest of them.
+func taskETag(task *entities.Task, subtasks []*entities.Task) string {
+	if len(subtasks) == 0 {
+		return etag(task.Version)
+	}
+
+	digest := fnv.New64a()
+	for _, subtask := range subtasks {
+		fmt.Fprintf(digest, "%s:%d;", subtask.ID, subtask.Version)
+	}
+	return `"` + strconv.FormatInt(task.Version, 10) + "-" + strconv.FormatUint(digest.Sum64(), 36) + `"`
+}
+
+func matchETag(header, tag string, weak bool) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+
+		if weak {
+			candidate = strings.TrimPrefix(candidate, "W/")
+		}
+
+		if candidate == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// A task tag with a subtask digest matches by the version alone.
+func ifMatch(r *http.Request, version int64) (required *int64, ok bool) {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		return nil, true
+	}
+
+	var candidates []string
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if i := strings.IndexByte(candidate, '-'); i > 0 && strings.HasPrefix(candidate, `"`) {
+			candidate = candidate[:i] + `"`
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	if !matchETag(strings.Join(candidates, ","), etag(version), false) {
+		return nil, false
+	}
+	return &version, true
+}
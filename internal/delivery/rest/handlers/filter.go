@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"github.com/turbekoff/todo/internal/delivery/rest/dto"
+	"github.com/turbekoff/todo/internal/domain/entities"
+	"github.com/turbekoff/todo/internal/domain/repositories"
+	"github.com/turbekoff/todo/internal/service"
+	"golang.org/x/exp/slog"
+)
+
+var ErrFilterAuthorization = errors.New("you don't have authorization to view this filter")
+
+func filterInput(bind *dto.FilterRequest) *service.FilterInput {
+	return &service.FilterInput{
+		Name:     bind.Name,
+		Query:    bind.Query,
+		TimeZone: bind.TimeZone,
+	}
+}
+
+func filterResponce(filter *entities.Filter) *dto.FilterResponce {
+	return &dto.FilterResponce{
+		ID:        filter.ID,
+		Name:      filter.Name,
+		Query:     filter.Query,
+		TimeZone:  filter.TimeZone,
+		CreatedAt: filter.CreatedAt,
+		UpdatedAt: filter.UpdatedAt,
+	}
+}
+
+func readFilter(r *http.Request, filterService service.FilterService) (*entities.Filter, int, error) {
+	filter, err := filterService.Read(chi.URLParam(r, "id"))
+	if errors.Is(err, repositories.ErrFilterNotFound) {
+		return nil, http.StatusNotFound, err
+	}
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	if filter.Owner != fmt.Sprint(r.Context().Value("auth.id")) {
+		return nil, http.StatusForbidden, ErrFilterAuthorization
+	}
+	return filter, http.StatusOK, nil
+}
+
+func NewCreateFilter(log *slog.Logger, filterService service.FilterService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "createFilter"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		bind := &dto.FilterRequest{}
+		if err := render.Bind(r, bind); err != nil {
+			log.Error("failed to load request", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		filter, err := filterService.Create(fmt.Sprint(r.Context().Value("auth.id")), filterInput(bind))
+		if err != nil {
+			log.Error("failed to create filter", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		render.Render(w, r, filterResponce(filter))
+	}
+}
+
+func NewReadFilters(log *slog.Logger, filterService service.FilterService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "readFilters"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		filters, err := filterService.ReadAllByOwner(fmt.Sprint(r.Context().Value("auth.id")))
+		if err != nil {
+			log.Error("failed to read filters", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusInternalServerError, Err: err.Error()})
+			return
+		}
+
+		result := &dto.FilterListResponce{}
+		for _, filter := range filters {
+			*result = append(*result, *filterResponce(filter))
+		}
+
+		render.Render(w, r, result)
+	}
+}
+
+func NewReadFilter(log *slog.Logger, filterService service.FilterService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "readFilter"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		filter, code, err := readFilter(r, filterService)
+		if err != nil {
+			log.Error("failed to read filter", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: code, Err: err.Error()})
+			return
+		}
+
+		render.Render(w, r, filterResponce(filter))
+	}
+}
+
+func NewUpdateFilter(log *slog.Logger, filterService service.FilterService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "updateFilter"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		filter, code, err := readFilter(r, filterService)
+		if err != nil {
+			log.Error("failed to read filter", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: code, Err: err.Error()})
+			return
+		}
+
+		bind := &dto.FilterRequest{}
+		if err := render.Bind(r, bind); err != nil {
+			log.Error("failed to load request", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		filter, err = filterService.Update(filter.ID, filterInput(bind))
+		if err != nil {
+			log.Error("failed to update filter", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		render.Render(w, r, filterResponce(filter))
+	}
+}
+
+func NewDeleteFilter(log *slog.Logger, filterService service.FilterService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "deleteFilter"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		filter, code, err := readFilter(r, filterService)
+		if err != nil {
+			log.Error("failed to read filter", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: code, Err: err.Error()})
+			return
+		}
+
+		if err := filterService.Delete(filter.ID); err != nil {
+			log.Error("failed to delete filter", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusInternalServerError, Err: err.Error()})
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+	}
+}
+
+func NewReadFilterTasks(log *slog.Logger, filterService service.FilterService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "readFilterTasks"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		filter, code, err := readFilter(r, filterService)
+		if err != nil {
+			log.Error("failed to read filter", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: code, Err: err.Error()})
+			return
+		}
+
+		values := r.URL.Query()
+		query := &service.FilterTaskQuery{
+			Sort:      values.Get("sort"),
+			Cursor:    values.Get("cursor"),
+			WithTotal: values.Get("total") == "true",
+		}
+
+		if limit := values.Get("limit"); limit != "" {
+			if query.Limit, err = strconv.Atoi(limit); err != nil {
+				log.Error("failed to load request", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+				render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: "limit must be a number"})
+				return
+			}
+		}
+
+		page, err := filterService.ReadTasks(filter.ID, query)
+		if err != nil {
+			log.Error("failed to read tasks", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		result := &dto.TaskPageResponce{Tasks: dto.TaskListResponce{}, NextCursor: page.NextCursor, Total: page.Total}
+		for _, task := range page.Tasks {
+			result.Tasks = append(result.Tasks, *taskResponce(task))
+		}
+
+		render.Render(w, r, result)
+	}
+}
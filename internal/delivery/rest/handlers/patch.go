@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/turbekoff/todo/pkg/mergepatch"
+)
+
+const mergePatchType = "application/merge-patch+json"
+
+var ErrPatchMediaType = errors.New("patch must be " + mergePatchType)
+
+// Plain JSON is accepted as a merge patch too.
+func mergePatch(r *http.Request, current interface{}) ([]byte, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != mergePatchType && mediaType != "application/json" {
+		return nil, ErrPatchMediaType
+	}
+
+	patch, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	document, err := json.Marshal(current)
+	if err != nil {
+		return nil, err
+	}
+
+	return mergepatch.Apply(document, patch)
+}
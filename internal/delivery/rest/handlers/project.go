@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"github.com/turbekoff/todo/internal/delivery/rest/dto"
+	"github.com/turbekoff/todo/internal/domain/entities"
+	"github.com/turbekoff/todo/internal/service"
+	"golang.org/x/exp/slog"
+)
+
+var ErrProjectAuthorization = errors.New("you don't have authorization to view this project")
+
+func projectInput(bind *dto.ProjectRequest) *service.ProjectInput {
+	return &service.ProjectInput{
+		Name:     bind.Name,
+		Color:    bind.Color,
+		Position: bind.Position,
+		Archived: bind.Archived,
+	}
+}
+
+func projectResponce(project *entities.Project) *dto.ProjectResponce {
+	return &dto.ProjectResponce{
+		ID:        project.ID,
+		Name:      project.Name,
+		Color:     project.Color,
+		Position:  project.Position,
+		Archived:  project.Archived,
+		CreatedAt: project.CreatedAt,
+		UpdatedAt: project.UpdatedAt,
+	}
+}
+
+func NewCreateProject(log *slog.Logger, projectService service.ProjectService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "createProject"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		bind := &dto.ProjectRequest{}
+		if err := render.Bind(r, bind); err != nil {
+			log.Error("failed to load request", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		project, err := projectService.Create(fmt.Sprint(r.Context().Value("auth.id")), projectInput(bind))
+		if err != nil {
+			log.Error("failed to create project", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		render.Render(w, r, projectResponce(project))
+	}
+}
+
+func NewReadProjects(log *slog.Logger, projectService service.ProjectService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "readProjects"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		projects, err := projectService.ReadAllByOwner(fmt.Sprint(r.Context().Value("auth.id")))
+		if err != nil {
+			log.Error("failed to read projects", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusInternalServerError, Err: err.Error()})
+			return
+		}
+
+		result := &dto.ProjectListResponce{}
+
+		for _, project := range projects {
+			*result = append(*result, *projectResponce(project))
+		}
+
+		render.Render(w, r, result)
+	}
+}
+
+func NewReadProject(log *slog.Logger, projectService service.ProjectService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "readProject"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		project, err := projectService.Read(chi.URLParam(r, "id"))
+		if err != nil {
+			log.Error("failed to read project", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusInternalServerError, Err: err.Error()})
+			return
+		}
+
+		if project.Owner != fmt.Sprint(r.Context().Value("auth.id")) {
+			log.Error("failed to read project", slog.Attr{Key: "error", Value: slog.StringValue(ErrProjectAuthorization.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusForbidden, Err: ErrProjectAuthorization.Error()})
+			return
+		}
+
+		render.Render(w, r, projectResponce(project))
+	}
+}
+
+func NewUpdateProject(log *slog.Logger, projectService service.ProjectService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "updateProject"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		project, err := projectService.Read(chi.URLParam(r, "id"))
+		if err != nil {
+			log.Error("failed to read project", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusInternalServerError, Err: err.Error()})
+			return
+		}
+
+		if project.Owner != fmt.Sprint(r.Context().Value("auth.id")) {
+			log.Error("failed to read project", slog.Attr{Key: "error", Value: slog.StringValue(ErrProjectAuthorization.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusForbidden, Err: ErrProjectAuthorization.Error()})
+			return
+		}
+
+		bind := &dto.ProjectRequest{}
+		if err := render.Bind(r, bind); err != nil {
+			log.Error("failed to load request", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		project, err = projectService.Update(project.ID, projectInput(bind))
+		if err != nil {
+			log.Error("failed to update project", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		render.Render(w, r, projectResponce(project))
+	}
+}
+
+func NewDeleteProject(log *slog.Logger, projectService service.ProjectService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "deleteProject"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		project, err := projectService.Read(chi.URLParam(r, "id"))
+		if err != nil {
+			log.Error("failed to read project", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusInternalServerError, Err: err.Error()})
+			return
+		}
+
+		if project.Owner != fmt.Sprint(r.Context().Value("auth.id")) {
+			log.Error("failed to read project", slog.Attr{Key: "error", Value: slog.StringValue(ErrProjectAuthorization.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusForbidden, Err: ErrProjectAuthorization.Error()})
+			return
+		}
+
+		var cascade bool
+		switch r.URL.Query().Get("tasks") {
+		case "", "inbox":
+		case "delete":
+			cascade = true
+		default:
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: "tasks must be either inbox or delete"})
+			return
+		}
+
+		if err := projectService.Delete(project.ID, cascade); err != nil {
+			log.Error("failed to delete project", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+	}
+}
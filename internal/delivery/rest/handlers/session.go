@@ -1,16 +1,42 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
 	"time"
 
+	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
 	"github.com/go-chi/render"
 	"github.com/turbekoff/todo/internal/delivery/rest/dto"
+	"github.com/turbekoff/todo/internal/domain/repositories"
 	"github.com/turbekoff/todo/internal/service"
 	"golang.org/x/exp/slog"
 )
 
+var ErrSessionAuthorization = errors.New("you don't have authorization to manage this session")
+
+// The port changes with every connection.
+func device(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func setSessionCookie(w http.ResponseWriter, tokens *service.Tokens) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     "session",
+		Value:    tokens.Refresh,
+		Path:     "/",
+		HttpOnly: true,
+		Expires:  tokens.RefreshExpireAt,
+	})
+}
+
 func NewSignin(log *slog.Logger, sessionService service.SessionService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		log = log.With(
@@ -25,34 +51,181 @@ func NewSignin(log *slog.Logger, sessionService service.SessionService) http.Han
 			return
 		}
 
-		tokens, err := sessionService.Create(r.RemoteAddr, bind.Name, bind.Password)
+		tokens, err := sessionService.Create(device(r), r.UserAgent(), bind.Name, bind.Password)
 		if err != nil {
 			log.Error("failed to create session", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
 			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
 			return
 		}
 
-		http.SetCookie(w, &http.Cookie{
-			Name:     "session",
-			Value:    tokens.Refresh,
-			Path:     "/",
-			HttpOnly: true,
-			Expires:  tokens.RefreshExpireAt,
-		})
+		setSessionCookie(w, tokens)
+		render.Render(w, r, &dto.SessionResponce{AccessToken: tokens.Access, RefreshToken: tokens.Refresh, ExpireAt: tokens.AccessExpireAt})
+	}
+}
+
+func NewRefresh(log *slog.Logger, sessionService service.SessionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "refresh"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		var token string
+		if cookie, err := r.Cookie("session"); err == nil && cookie.Value != "" {
+			token = cookie.Value
+		} else {
+			bind := &dto.RefreshSessionRequest{}
+			if err := render.Bind(r, bind); err != nil {
+				log.Error("failed to load request", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+				render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+				return
+			}
+			token = bind.RefreshToken
+		}
+
+		tokens, err := sessionService.Refresh(device(r), token)
+		if err != nil {
+			code := http.StatusInternalServerError
+			switch {
+			case errors.Is(err, repositories.ErrSessionNotFound):
+				code = http.StatusUnauthorized
+			case errors.Is(err, service.ErrRefreshTokenExpired):
+				code = http.StatusGone
+			case errors.Is(err, service.ErrRefreshTokenReused):
+				code = http.StatusConflict
+			case errors.Is(err, service.ErrDeviceMismatch):
+				code = http.StatusForbidden
+			}
+
+			log.Error("failed to refresh session", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: code, Err: err.Error()})
+			return
+		}
 
+		setSessionCookie(w, tokens)
 		render.Render(w, r, &dto.SessionResponce{AccessToken: tokens.Access, RefreshToken: tokens.Refresh, ExpireAt: tokens.AccessExpireAt})
 	}
 }
 
+func clearSessionCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     "session",
+		Value:    "",
+		Path:     "/",
+		HttpOnly: true,
+		Expires:  time.Now(),
+		MaxAge:   -1,
+	})
+}
+
 func NewLogout(log *slog.Logger, sessionService service.SessionService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		http.SetCookie(w, &http.Cookie{
-			Name:     "session",
-			Value:    "",
-			Path:     "/",
-			HttpOnly: true,
-			Expires:  time.Now(),
-			MaxAge:   -1,
-		})
+		log := log.With(
+			slog.String("handler", "logout"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		err := sessionService.Delete(fmt.Sprint(r.Context().Value("auth.session")))
+		if err != nil {
+			log.Error("failed to delete session", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusInternalServerError, Err: err.Error()})
+			return
+		}
+
+		clearSessionCookie(w)
+	}
+}
+
+func NewLogoutAll(log *slog.Logger, sessionService service.SessionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "logoutAll"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		err := sessionService.DeleteAllByOwner(fmt.Sprint(r.Context().Value("auth.id")))
+		if err != nil {
+			log.Error("failed to delete sessions", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusInternalServerError, Err: err.Error()})
+			return
+		}
+
+		clearSessionCookie(w)
+	}
+}
+
+func NewReadSessions(log *slog.Logger, sessionService service.SessionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "readSessions"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		sessions, err := sessionService.ReadAllByOwner(fmt.Sprint(r.Context().Value("auth.id")))
+		if err != nil {
+			log.Error("failed to read sessions", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusInternalServerError, Err: err.Error()})
+			return
+		}
+
+		current := fmt.Sprint(r.Context().Value("auth.session"))
+		result := &dto.SessionListResponce{}
+
+		for _, session := range sessions {
+			if time.Now().After(session.ExpireAt) {
+				continue
+			}
+
+			*result = append(*result, dto.SessionInfoResponce{
+				ID:         session.ID,
+				Device:     session.Device,
+				UserAgent:  session.UserAgent,
+				Current:    session.ID == current,
+				CreatedAt:  session.CreatedAt,
+				LastUsedAt: session.LastUsedAt,
+				ExpireAt:   session.ExpireAt,
+			})
+		}
+
+		render.Render(w, r, result)
+	}
+}
+
+func NewDeleteSession(log *slog.Logger, sessionService service.SessionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "deleteSession"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		session, err := sessionService.Read(chi.URLParam(r, "id"))
+		if err != nil {
+			code := http.StatusInternalServerError
+			if errors.Is(err, repositories.ErrSessionNotFound) {
+				code = http.StatusNotFound
+			}
+
+			log.Error("failed to read session", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: code, Err: err.Error()})
+			return
+		}
+
+		if session.Owner != fmt.Sprint(r.Context().Value("auth.id")) {
+			log.Error("failed to read session", slog.Attr{Key: "error", Value: slog.StringValue(ErrSessionAuthorization.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusForbidden, Err: ErrSessionAuthorization.Error()})
+			return
+		}
+
+		if err := sessionService.Delete(session.ID); err != nil {
+			log.Error("failed to delete session", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusInternalServerError, Err: err.Error()})
+			return
+		}
+
+		if session.ID == fmt.Sprint(r.Context().Value("auth.session")) {
+			clearSessionCookie(w)
+		}
+
+		render.Status(r, http.StatusOK)
 	}
 }
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"github.com/turbekoff/todo/internal/delivery/rest/dto"
+	"github.com/turbekoff/todo/internal/service"
+	"golang.org/x/exp/slog"
+)
+
+func NewReadTags(log *slog.Logger, taskService service.TaskService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "readTags"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		tags, err := taskService.ReadTags(fmt.Sprint(r.Context().Value("auth.id")))
+		if err != nil {
+			log.Error("failed to read tags", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusInternalServerError, Err: err.Error()})
+			return
+		}
+
+		result := &dto.TagListResponce{}
+
+		for _, tag := range tags {
+			*result = append(*result, dto.TagResponce{Name: tag.Name, Count: tag.Count})
+		}
+
+		render.Render(w, r, result)
+	}
+}
@@ -1,21 +1,107 @@
 package handlers
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
 	"github.com/go-chi/render"
 	"github.com/turbekoff/todo/internal/delivery/rest/dto"
+	"github.com/turbekoff/todo/internal/domain/entities"
 	"github.com/turbekoff/todo/internal/domain/repositories"
 	"github.com/turbekoff/todo/internal/service"
+	"github.com/turbekoff/todo/pkg/markdown"
+	"github.com/turbekoff/todo/pkg/quickadd"
 	"golang.org/x/exp/slog"
 )
 
 var ErrTaskAuthorization = errors.New("you don't have authorization to view this task")
 
+func taskInput(bind *dto.TaskRequest) *service.TaskInput {
+	return &service.TaskInput{
+		Project:   bind.Project,
+		Parent:    bind.Parent,
+		Name:      bind.Name,
+		Notes:     bind.Notes,
+		Status:    bind.Status,
+		Completed: bind.Completed,
+		Priority:  bind.Priority,
+		Tags:      bind.Tags,
+		DueDate:   bind.DueDate,
+		DueTime:   bind.DueTime,
+		TimeZone:  bind.TimeZone,
+
+		Recurrence:          bind.Recurrence,
+		RecurFromCompletion: bind.RecurFromCompletion,
+
+		CompleteSubtasks: bind.CompleteSubtasks,
+
+		BlockedBy: bind.BlockedBy,
+	}
+}
+
+func taskRequest(task *entities.Task) *dto.TaskRequest {
+	return &dto.TaskRequest{
+		Project:   task.Project,
+		Parent:    task.Parent,
+		Name:      task.Name,
+		Notes:     task.Notes,
+		Status:    task.Status,
+		Completed: task.Completed,
+		Priority:  task.Priority.String(),
+		Tags:      task.Tags,
+		DueDate:   task.DueDate,
+		DueTime:   task.DueTime,
+		TimeZone:  task.TimeZone,
+
+		Recurrence:          task.Recurrence,
+		RecurFromCompletion: task.RecurFromCompletion,
+
+		BlockedBy: task.BlockedBy,
+	}
+}
+
+func taskResponce(task *entities.Task) *dto.TaskResponce {
+	tags := task.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return &dto.TaskResponce{
+		ID:          task.ID,
+		Project:     task.Project,
+		Parent:      task.Parent,
+		Name:        task.Name,
+		Notes:       task.Notes,
+		Status:      task.Status,
+		Completed:   task.Completed,
+		Archived:    task.Archived,
+		Priority:    task.Priority.String(),
+		Tags:        tags,
+		DueDate:     task.DueDate,
+		DueTime:     task.DueTime,
+		TimeZone:    task.TimeZone,
+		DueAt:       task.DueAt,
+		Position:    task.Position,
+		CompletedAt: task.CompletedAt,
+		CreatedAt:   task.CreatedAt,
+		UpdatedAt:   task.UpdatedAt,
+		DeletedAt:   task.DeletedAt,
+
+		Recurrence:          task.Recurrence,
+		RecurFromCompletion: task.RecurFromCompletion,
+
+		BlockedBy: task.BlockedBy,
+		Blocked:   task.Blocked,
+	}
+}
+
 func NewCreateTask(log *slog.Logger, taskService service.TaskService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		log = log.With(
@@ -30,7 +116,7 @@ func NewCreateTask(log *slog.Logger, taskService service.TaskService) http.Handl
 			return
 		}
 
-		err := taskService.Create(fmt.Sprint(r.Context().Value("auth.id")), bind.Name, bind.Completed)
+		_, err := taskService.Create(fmt.Sprint(r.Context().Value("auth.id")), taskInput(bind))
 		if err != nil {
 			log.Error("failed to create task", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
 			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
@@ -41,6 +127,85 @@ func NewCreateTask(log *slog.Logger, taskService service.TaskService) http.Handl
 	}
 }
 
+// Underscores in the project name stand for spaces.
+func findProject(projectService service.ProjectService, owner, name string) (string, int, error) {
+	projects, err := projectService.ReadAllByOwner(owner)
+	if err != nil {
+		return "", http.StatusInternalServerError, err
+	}
+
+	for _, project := range projects {
+		if strings.EqualFold(project.Name, name) || strings.EqualFold(project.Name, strings.ReplaceAll(name, "_", " ")) {
+			return project.ID, http.StatusOK, nil
+		}
+	}
+	return "", http.StatusBadRequest, fmt.Errorf("project %q doesn't exist", name)
+}
+
+func NewQuickAddTask(log *slog.Logger, taskService service.TaskService, projectService service.ProjectService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "quickAddTask"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		bind := &dto.QuickTaskRequest{}
+		if err := render.Bind(r, bind); err != nil {
+			log.Error("failed to load request", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		loc, err := time.LoadLocation(bind.TimeZone)
+		if err != nil {
+			log.Error("failed to load time zone", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: "unknown time zone " + bind.TimeZone})
+			return
+		}
+
+		parsed, err := quickadd.Parse(bind.Text, time.Now(), loc)
+		if err != nil {
+			log.Error("failed to parse task", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		owner := fmt.Sprint(r.Context().Value("auth.id"))
+		project := bind.Project
+		if project == "" && parsed.Project != "" {
+			var code int
+			if project, code, err = findProject(projectService, owner, parsed.Project); err != nil {
+				log.Error("failed to find project", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+				render.Render(w, r, &dto.ErrResponce{Code: code, Err: err.Error()})
+				return
+			}
+		}
+
+		task, err := taskService.Create(owner, &service.TaskInput{
+			Project:    project,
+			Name:       parsed.Name,
+			Priority:   parsed.Priority,
+			Tags:       parsed.Tags,
+			DueDate:    parsed.DueDate,
+			DueTime:    parsed.DueTime,
+			TimeZone:   loc.String(),
+			Recurrence: parsed.Recurrence,
+		})
+		if errors.Is(err, service.ErrProjectAuthorization) {
+			log.Error("failed to create task", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusForbidden, Err: err.Error()})
+			return
+		}
+		if err != nil {
+			log.Error("failed to create task", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		render.Render(w, r, taskResponce(task))
+	}
+}
+
 func NewReadTask(log *slog.Logger, taskService service.TaskService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		log = log.With(
@@ -50,6 +215,13 @@ func NewReadTask(log *slog.Logger, taskService service.TaskService) http.Handler
 
 		log.Debug(chi.URLParam(r, "id"))
 
+		mode := r.URL.Query().Get("render")
+		if mode != "" && mode != "html" {
+			log.Error("failed to load request", slog.Attr{Key: "error", Value: slog.StringValue("unknown render mode " + mode)})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: "render must be html"})
+			return
+		}
+
 		task, err := taskService.Read(chi.URLParam(r, "id"))
 		if err != nil {
 			log.Error("failed to read task", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
@@ -63,13 +235,41 @@ func NewReadTask(log *slog.Logger, taskService service.TaskService) http.Handler
 			return
 		}
 
-		render.Render(w, r, &dto.TaskResponce{
-			ID:        task.ID,
-			Name:      task.Name,
-			Completed: task.Completed,
-			CreatedAt: task.CreatedAt,
-			UpdatedAt: task.UpdatedAt,
-		})
+		subtasks, err := taskService.ReadSubtasks(task.ID)
+		if err != nil {
+			log.Error("failed to read subtasks", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusInternalServerError, Err: err.Error()})
+			return
+		}
+
+		tag := taskETag(task, subtasks)
+		w.Header().Set("ETag", tag)
+		if header := r.Header.Get("If-None-Match"); header != "" && matchETag(header, tag, true) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		result := taskResponce(task)
+		if mode == "html" && task.Notes != "" {
+			if result.NotesHTML, err = markdown.ToHTML(task.Notes); err != nil {
+				log.Error("failed to render notes", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+				render.Render(w, r, &dto.ErrResponce{Code: http.StatusInternalServerError, Err: err.Error()})
+				return
+			}
+		}
+
+		if len(subtasks) > 0 {
+			result.Progress = &dto.ProgressResponce{Total: len(subtasks)}
+		}
+
+		for _, subtask := range subtasks {
+			result.Subtasks = append(result.Subtasks, *taskResponce(subtask))
+			if subtask.Completed {
+				result.Progress.Completed++
+			}
+		}
+
+		render.Render(w, r, result)
 	}
 }
 
@@ -80,25 +280,155 @@ func NewReadTasks(log *slog.Logger, taskService service.TaskService) http.Handle
 			slog.String("requestID", middleware.GetReqID(r.Context())),
 		)
 
-		tasks, err := taskService.ReadAllByOwner(fmt.Sprint(r.Context().Value("auth.id")))
-		if err != nil && !errors.Is(err, repositories.ErrSessionNotFound) {
+		values := r.URL.Query()
+		query := &service.TaskQuery{
+			Project:   values.Get("project"),
+			Parent:    values.Get("parent"),
+			Due:       values.Get("due"),
+			TimeZone:  values.Get("tz"),
+			Tags:      values["tag"],
+			TagMatch:  values.Get("tagMatch"),
+			Sort:      values.Get("sort"),
+			Cursor:    values.Get("cursor"),
+			WithTotal: values.Get("total") == "true",
+			Archived:  values.Get("archived") == "true",
+		}
+
+		if blocked := values.Get("blocked"); blocked != "" {
+			value, err := strconv.ParseBool(blocked)
+			if err != nil {
+				log.Error("failed to load request", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+				render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: "blocked must be either true or false"})
+				return
+			}
+			query.Blocked = &value
+		}
+
+		if limit := values.Get("limit"); limit != "" {
+			var err error
+			if query.Limit, err = strconv.Atoi(limit); err != nil {
+				log.Error("failed to load request", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+				render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: "limit must be a number"})
+				return
+			}
+		}
+
+		page, err := taskService.ReadPage(fmt.Sprint(r.Context().Value("auth.id")), query)
+		if err != nil {
 			log.Error("failed to read tasks", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
 			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
 			return
 		}
 
-		result := &dto.TaskListResponce{}
+		result := &dto.TaskPageResponce{Tasks: dto.TaskListResponce{}, NextCursor: page.NextCursor, Total: page.Total}
+
+		for _, task := range page.Tasks {
+			result.Tasks = append(result.Tasks, *taskResponce(task))
+		}
 
+		render.Render(w, r, result)
+	}
+}
+
+func NewSearchTasks(log *slog.Logger, taskService service.TaskService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "searchTasks"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		var limit int
+		if value := r.URL.Query().Get("limit"); value != "" {
+			var err error
+			if limit, err = strconv.Atoi(value); err != nil {
+				log.Error("failed to load request", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+				render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: "limit must be a number"})
+				return
+			}
+		}
+
+		archived := r.URL.Query().Get("archived") == "true"
+		tasks, err := taskService.Search(fmt.Sprint(r.Context().Value("auth.id")), r.URL.Query().Get("q"), archived, limit)
+		if err != nil {
+			log.Error("failed to search tasks", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		result := dto.TaskListResponce{}
 		for _, task := range tasks {
-			*result = append(*result, dto.TaskResponce{
-				ID:        task.ID,
-				Name:      task.Name,
-				Completed: task.Completed,
-				CreatedAt: task.CreatedAt,
-				UpdatedAt: task.UpdatedAt,
+			result = append(result, *taskResponce(task))
+		}
+
+		render.Render(w, r, &result)
+	}
+}
+
+func NewArchiveTasks(log *slog.Logger, taskService service.TaskService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "archiveTasks"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		bind := &dto.ArchiveRequest{}
+		if err := render.Bind(r, bind); err != nil {
+			log.Error("failed to load request", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		count, err := taskService.Archive(fmt.Sprint(r.Context().Value("auth.id")), bind.OlderThanDays)
+		if err != nil {
+			log.Error("failed to archive tasks", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		render.Render(w, r, &dto.ArchiveResponce{Archived: count})
+	}
+}
+
+func NewBatchTasks(log *slog.Logger, taskService service.TaskService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "batchTasks"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		bind := &dto.BatchRequest{}
+		if err := render.Bind(r, bind); err != nil {
+			log.Error("failed to load request", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		var operations []*service.TaskOperation
+		for _, operation := range bind.Operations {
+			operations = append(operations, &service.TaskOperation{
+				Op:      operation.Op,
+				IDs:     operation.IDs,
+				Project: operation.Project,
+				Tag:     operation.Tag,
 			})
 		}
 
+		results, err := taskService.Batch(fmt.Sprint(r.Context().Value("auth.id")), operations)
+		if err != nil {
+			log.Error("failed to run batch", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		result := &dto.BatchResponce{Results: []dto.TaskOperationResponce{}}
+		for _, item := range results {
+			responce := dto.TaskOperationResponce{ID: item.ID, Op: item.Op, OK: item.Err == nil}
+			if item.Err != nil {
+				responce.Error = item.Err.Error()
+			}
+			result.Results = append(result.Results, responce)
+		}
+
 		render.Render(w, r, result)
 	}
 }
@@ -123,6 +453,13 @@ func NewUpdateTask(log *slog.Logger, taskService service.TaskService) http.Handl
 			return
 		}
 
+		version, ok := ifMatch(r, task.Version)
+		if !ok {
+			log.Error("failed to read task", slog.Attr{Key: "error", Value: slog.StringValue(repositories.ErrTaskConflict.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusPreconditionFailed, Err: repositories.ErrTaskConflict.Error()})
+			return
+		}
+
 		bind := &dto.TaskRequest{}
 		if err := render.Bind(r, bind); err != nil {
 			log.Error("failed to load request", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
@@ -130,20 +467,160 @@ func NewUpdateTask(log *slog.Logger, taskService service.TaskService) http.Handl
 			return
 		}
 
-		task, err = taskService.Update(chi.URLParam(r, "id"), bind.Name, bind.Completed)
+		input := taskInput(bind)
+		input.Version = version
+
+		task, err = taskService.Update(chi.URLParam(r, "id"), input)
+		if errors.Is(err, repositories.ErrTaskConflict) {
+			log.Error("failed to update task", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusPreconditionFailed, Err: err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrTaskTransition) {
+			log.Error("failed to update task", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusConflict, Err: err.Error()})
+			return
+		}
 		if err != nil {
 			log.Error("failed to update task", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
 			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
 			return
 		}
 
-		render.Render(w, r, &dto.TaskResponce{
-			ID:        task.ID,
-			Name:      task.Name,
-			Completed: task.Completed,
-			CreatedAt: task.CreatedAt,
-			UpdatedAt: task.UpdatedAt,
-		})
+		w.Header().Set("ETag", etag(task.Version))
+		render.Render(w, r, taskResponce(task))
+	}
+}
+
+func NewPatchTask(log *slog.Logger, taskService service.TaskService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "patchTask"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		task, err := taskService.Read(chi.URLParam(r, "id"))
+		if err != nil {
+			log.Error("failed to read task", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusInternalServerError, Err: err.Error()})
+			return
+		}
+
+		if task.Owner != fmt.Sprint(r.Context().Value("auth.id")) {
+			log.Error("failed to read task", slog.Attr{Key: "error", Value: slog.StringValue(ErrTaskAuthorization.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusForbidden, Err: ErrTaskAuthorization.Error()})
+			return
+		}
+
+		version, ok := ifMatch(r, task.Version)
+		if !ok {
+			log.Error("failed to read task", slog.Attr{Key: "error", Value: slog.StringValue(repositories.ErrTaskConflict.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusPreconditionFailed, Err: repositories.ErrTaskConflict.Error()})
+			return
+		}
+
+		document, err := mergePatch(r, taskRequest(task))
+		if errors.Is(err, ErrPatchMediaType) {
+			log.Error("failed to load request", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusUnsupportedMediaType, Err: err.Error()})
+			return
+		}
+
+		bind := &dto.TaskRequest{}
+		if err == nil {
+			err = json.Unmarshal(document, bind)
+		}
+		if err != nil {
+			log.Error("failed to load request", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		// The patch was applied to the task read above, so the update must not
+		// go through when the task changed since, even without If-Match.
+		conflict := http.StatusPreconditionFailed
+		if version == nil {
+			version, conflict = &task.Version, http.StatusConflict
+		}
+
+		input := taskInput(bind)
+		input.Version = version
+
+		task, err = taskService.Update(task.ID, input)
+		if errors.Is(err, repositories.ErrTaskConflict) {
+			log.Error("failed to update task", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: conflict, Err: err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrTaskTransition) {
+			log.Error("failed to update task", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusConflict, Err: err.Error()})
+			return
+		}
+		if err != nil {
+			log.Error("failed to update task", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		w.Header().Set("ETag", etag(task.Version))
+		render.Render(w, r, taskResponce(task))
+	}
+}
+
+func NewMoveTask(log *slog.Logger, taskService service.TaskService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "moveTask"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		task, err := taskService.Read(chi.URLParam(r, "id"))
+		if err != nil {
+			log.Error("failed to read task", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusInternalServerError, Err: err.Error()})
+			return
+		}
+
+		if task.Owner != fmt.Sprint(r.Context().Value("auth.id")) {
+			log.Error("failed to read task", slog.Attr{Key: "error", Value: slog.StringValue(ErrTaskAuthorization.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusForbidden, Err: ErrTaskAuthorization.Error()})
+			return
+		}
+
+		version, ok := ifMatch(r, task.Version)
+		if !ok {
+			log.Error("failed to read task", slog.Attr{Key: "error", Value: slog.StringValue(repositories.ErrTaskConflict.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusPreconditionFailed, Err: repositories.ErrTaskConflict.Error()})
+			return
+		}
+
+		bind := &dto.MoveTaskRequest{}
+		if err := render.Bind(r, bind); err != nil {
+			log.Error("failed to load request", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		task, err = taskService.Move(task.ID, &service.TaskMoveInput{Before: bind.Before, After: bind.After, Version: version})
+		if errors.Is(err, repositories.ErrTaskConflict) {
+			log.Error("failed to move task", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusPreconditionFailed, Err: err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrAnchorAuthorization) {
+			log.Error("failed to move task", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusForbidden, Err: err.Error()})
+			return
+		}
+		if err != nil {
+			log.Error("failed to move task", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		w.Header().Set("ETag", etag(task.Version))
+		render.Render(w, r, taskResponce(task))
 	}
 }
 
@@ -167,7 +644,19 @@ func NewDeleteTask(log *slog.Logger, taskService service.TaskService) http.Handl
 			return
 		}
 
-		err = taskService.Delete(chi.URLParam(r, "id"))
+		version, ok := ifMatch(r, task.Version)
+		if !ok {
+			log.Error("failed to read task", slog.Attr{Key: "error", Value: slog.StringValue(repositories.ErrTaskConflict.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusPreconditionFailed, Err: repositories.ErrTaskConflict.Error()})
+			return
+		}
+
+		err = taskService.Delete(chi.URLParam(r, "id"), version)
+		if errors.Is(err, repositories.ErrTaskConflict) {
+			log.Error("failed to delete task", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusPreconditionFailed, Err: err.Error()})
+			return
+		}
 		if err != nil {
 			log.Error("failed to delete task", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
 			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"github.com/turbekoff/todo/internal/delivery/rest/dto"
+	"github.com/turbekoff/todo/internal/domain/entities"
+	"github.com/turbekoff/todo/internal/domain/repositories"
+	"github.com/turbekoff/todo/internal/service"
+	"golang.org/x/exp/slog"
+)
+
+var ErrTemplateAuthorization = errors.New("you don't have authorization to view this template")
+
+func templateTaskInputs(binds []dto.TemplateTaskRequest) []*service.TemplateTaskInput {
+	var inputs []*service.TemplateTaskInput
+	for _, bind := range binds {
+		inputs = append(inputs, &service.TemplateTaskInput{
+			Name:      bind.Name,
+			Notes:     bind.Notes,
+			Priority:  bind.Priority,
+			Tags:      bind.Tags,
+			DueOffset: bind.DueOffset,
+			DueTime:   bind.DueTime,
+			Subtasks:  templateTaskInputs(bind.Subtasks),
+		})
+	}
+	return inputs
+}
+
+func templateInput(bind *dto.TemplateRequest) *service.TemplateInput {
+	return &service.TemplateInput{
+		Name:  bind.Name,
+		Tasks: templateTaskInputs(bind.Tasks),
+	}
+}
+
+func templateTasksResponce(tasks []*entities.TemplateTask) []dto.TemplateTaskResponce {
+	var result []dto.TemplateTaskResponce
+	for _, task := range tasks {
+		result = append(result, dto.TemplateTaskResponce{
+			Name:      task.Name,
+			Notes:     task.Notes,
+			Priority:  task.Priority.String(),
+			Tags:      task.Tags,
+			DueOffset: task.DueOffset,
+			DueTime:   task.DueTime,
+			Subtasks:  templateTasksResponce(task.Subtasks),
+		})
+	}
+	return result
+}
+
+func templateResponce(template *entities.Template) *dto.TemplateResponce {
+	return &dto.TemplateResponce{
+		ID:        template.ID,
+		Name:      template.Name,
+		Tasks:     templateTasksResponce(template.Tasks),
+		CreatedAt: template.CreatedAt,
+		UpdatedAt: template.UpdatedAt,
+	}
+}
+
+func readTemplate(r *http.Request, templateService service.TemplateService) (*entities.Template, int, error) {
+	template, err := templateService.Read(chi.URLParam(r, "id"))
+	if errors.Is(err, repositories.ErrTemplateNotFound) {
+		return nil, http.StatusNotFound, err
+	}
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	if template.Owner != fmt.Sprint(r.Context().Value("auth.id")) {
+		return nil, http.StatusForbidden, ErrTemplateAuthorization
+	}
+	return template, http.StatusOK, nil
+}
+
+func NewCreateTemplate(log *slog.Logger, templateService service.TemplateService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "createTemplate"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		bind := &dto.TemplateRequest{}
+		if err := render.Bind(r, bind); err != nil {
+			log.Error("failed to load request", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		template, err := templateService.Create(fmt.Sprint(r.Context().Value("auth.id")), templateInput(bind))
+		if err != nil {
+			log.Error("failed to create template", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		render.Render(w, r, templateResponce(template))
+	}
+}
+
+func NewReadTemplates(log *slog.Logger, templateService service.TemplateService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "readTemplates"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		templates, err := templateService.ReadAllByOwner(fmt.Sprint(r.Context().Value("auth.id")))
+		if err != nil {
+			log.Error("failed to read templates", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusInternalServerError, Err: err.Error()})
+			return
+		}
+
+		result := &dto.TemplateListResponce{}
+		for _, template := range templates {
+			*result = append(*result, *templateResponce(template))
+		}
+
+		render.Render(w, r, result)
+	}
+}
+
+func NewReadTemplate(log *slog.Logger, templateService service.TemplateService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "readTemplate"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		template, code, err := readTemplate(r, templateService)
+		if err != nil {
+			log.Error("failed to read template", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: code, Err: err.Error()})
+			return
+		}
+
+		render.Render(w, r, templateResponce(template))
+	}
+}
+
+func NewUpdateTemplate(log *slog.Logger, templateService service.TemplateService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "updateTemplate"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		template, code, err := readTemplate(r, templateService)
+		if err != nil {
+			log.Error("failed to read template", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: code, Err: err.Error()})
+			return
+		}
+
+		bind := &dto.TemplateRequest{}
+		if err := render.Bind(r, bind); err != nil {
+			log.Error("failed to load request", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		template, err = templateService.Update(template.ID, templateInput(bind))
+		if err != nil {
+			log.Error("failed to update template", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		render.Render(w, r, templateResponce(template))
+	}
+}
+
+func NewDeleteTemplate(log *slog.Logger, templateService service.TemplateService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "deleteTemplate"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		template, code, err := readTemplate(r, templateService)
+		if err != nil {
+			log.Error("failed to read template", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: code, Err: err.Error()})
+			return
+		}
+
+		if err := templateService.Delete(template.ID); err != nil {
+			log.Error("failed to delete template", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusInternalServerError, Err: err.Error()})
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+	}
+}
+
+func NewInstantiateTemplate(log *slog.Logger, templateService service.TemplateService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "instantiateTemplate"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		template, code, err := readTemplate(r, templateService)
+		if err != nil {
+			log.Error("failed to read template", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: code, Err: err.Error()})
+			return
+		}
+
+		bind := &dto.InstantiateTemplateRequest{}
+		if err := render.Bind(r, bind); err != nil {
+			log.Error("failed to load request", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		tasks, err := templateService.Instantiate(template.ID, &service.TemplateInstanceInput{
+			Project:  bind.Project,
+			Date:     bind.Date,
+			TimeZone: bind.TimeZone,
+			Values:   bind.Values,
+		})
+		if errors.Is(err, service.ErrProjectAuthorization) {
+			log.Error("failed to instantiate template", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusForbidden, Err: err.Error()})
+			return
+		}
+		if err != nil {
+			log.Error("failed to instantiate template", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		result := &dto.TaskListResponce{}
+		for _, task := range tasks {
+			*result = append(*result, *taskResponce(task))
+		}
+
+		render.Render(w, r, result)
+	}
+}
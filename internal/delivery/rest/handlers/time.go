@@ -0,0 +1,309 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"github.com/turbekoff/todo/internal/delivery/rest/dto"
+	"github.com/turbekoff/todo/internal/domain/entities"
+	"github.com/turbekoff/todo/internal/domain/repositories"
+	"github.com/turbekoff/todo/internal/service"
+	"golang.org/x/exp/slog"
+)
+
+var ErrTimeEntryAuthorization = errors.New("you don't have authorization to manage this time entry")
+
+func timeEntryResponce(entry *entities.TimeEntry) *dto.TimeEntryResponce {
+	end := time.Now()
+	if entry.End != nil {
+		end = *entry.End
+	}
+
+	return &dto.TimeEntryResponce{
+		ID:        entry.ID,
+		Task:      entry.Task,
+		Start:     entry.Start,
+		End:       entry.End,
+		Running:   entry.End == nil,
+		Seconds:   int64(end.Sub(entry.Start) / time.Second),
+		Note:      entry.Note,
+		CreatedAt: entry.CreatedAt,
+		UpdatedAt: entry.UpdatedAt,
+	}
+}
+
+func timeTotalsResponce(totals []*service.TimeTotal) []dto.TimeTotalResponce {
+	result := []dto.TimeTotalResponce{}
+	for _, total := range totals {
+		result = append(result, dto.TimeTotalResponce{Key: total.Key, Seconds: int64(total.Duration / time.Second)})
+	}
+	return result
+}
+
+func NewReadTimeEntries(log *slog.Logger, taskService service.TaskService, timeService service.TimeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "readTimeEntries"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		task, err := taskService.Read(chi.URLParam(r, "id"))
+		if err != nil {
+			log.Error("failed to read task", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusInternalServerError, Err: err.Error()})
+			return
+		}
+
+		if task.Owner != fmt.Sprint(r.Context().Value("auth.id")) {
+			log.Error("failed to read task", slog.Attr{Key: "error", Value: slog.StringValue(ErrTaskAuthorization.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusForbidden, Err: ErrTaskAuthorization.Error()})
+			return
+		}
+
+		entries, err := timeService.ReadAllByTask(task.ID)
+		if err != nil {
+			log.Error("failed to read time entries", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusInternalServerError, Err: err.Error()})
+			return
+		}
+
+		result := dto.TimeEntryListResponce{}
+		for _, entry := range entries {
+			result = append(result, *timeEntryResponce(entry))
+		}
+
+		render.Render(w, r, &result)
+	}
+}
+
+func NewCreateTimeEntry(log *slog.Logger, taskService service.TaskService, timeService service.TimeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "createTimeEntry"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		task, err := taskService.Read(chi.URLParam(r, "id"))
+		if err != nil {
+			log.Error("failed to read task", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusInternalServerError, Err: err.Error()})
+			return
+		}
+
+		if task.Owner != fmt.Sprint(r.Context().Value("auth.id")) {
+			log.Error("failed to read task", slog.Attr{Key: "error", Value: slog.StringValue(ErrTaskAuthorization.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusForbidden, Err: ErrTaskAuthorization.Error()})
+			return
+		}
+
+		bind := &dto.TimeEntryRequest{}
+		if err := render.Bind(r, bind); err != nil {
+			log.Error("failed to load request", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		entry, err := timeService.Create(task.ID, &service.TimeEntryInput{Start: bind.Start, End: bind.End, Note: bind.Note})
+		if err != nil {
+			log.Error("failed to create time entry", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		render.Render(w, r, timeEntryResponce(entry))
+	}
+}
+
+func NewStartTimer(log *slog.Logger, taskService service.TaskService, timeService service.TimeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "startTimer"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		task, err := taskService.Read(chi.URLParam(r, "id"))
+		if err != nil {
+			log.Error("failed to read task", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusInternalServerError, Err: err.Error()})
+			return
+		}
+
+		if task.Owner != fmt.Sprint(r.Context().Value("auth.id")) {
+			log.Error("failed to read task", slog.Attr{Key: "error", Value: slog.StringValue(ErrTaskAuthorization.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusForbidden, Err: ErrTaskAuthorization.Error()})
+			return
+		}
+
+		bind := &dto.TimerRequest{}
+		if r.ContentLength != 0 {
+			if err := render.Bind(r, bind); err != nil {
+				log.Error("failed to load request", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+				render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+				return
+			}
+		}
+
+		entry, err := timeService.Start(task.ID, bind.Note)
+		if errors.Is(err, repositories.ErrTimerRunning) {
+			log.Error("failed to start timer", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusConflict, Err: err.Error()})
+			return
+		}
+		if err != nil {
+			log.Error("failed to start timer", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		render.Render(w, r, timeEntryResponce(entry))
+	}
+}
+
+func NewStopTimer(log *slog.Logger, taskService service.TaskService, timeService service.TimeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "stopTimer"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		task, err := taskService.Read(chi.URLParam(r, "id"))
+		if err != nil {
+			log.Error("failed to read task", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusInternalServerError, Err: err.Error()})
+			return
+		}
+
+		if task.Owner != fmt.Sprint(r.Context().Value("auth.id")) {
+			log.Error("failed to read task", slog.Attr{Key: "error", Value: slog.StringValue(ErrTaskAuthorization.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusForbidden, Err: ErrTaskAuthorization.Error()})
+			return
+		}
+
+		entry, err := timeService.Stop(task.ID)
+		if errors.Is(err, service.ErrTimerNotRunning) {
+			log.Error("failed to stop timer", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusConflict, Err: err.Error()})
+			return
+		}
+		if err != nil {
+			log.Error("failed to stop timer", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusInternalServerError, Err: err.Error()})
+			return
+		}
+
+		render.Render(w, r, timeEntryResponce(entry))
+	}
+}
+
+func readTimeEntry(r *http.Request, timeService service.TimeService) (*entities.TimeEntry, int, error) {
+	entry, err := timeService.Read(chi.URLParam(r, "entry"))
+	if errors.Is(err, repositories.ErrTimeEntryNotFound) {
+		return nil, http.StatusNotFound, err
+	}
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	if entry.Owner != fmt.Sprint(r.Context().Value("auth.id")) {
+		return nil, http.StatusForbidden, ErrTimeEntryAuthorization
+	}
+
+	if entry.Task != chi.URLParam(r, "id") {
+		return nil, http.StatusNotFound, repositories.ErrTimeEntryNotFound
+	}
+	return entry, http.StatusOK, nil
+}
+
+func NewUpdateTimeEntry(log *slog.Logger, timeService service.TimeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "updateTimeEntry"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		entry, code, err := readTimeEntry(r, timeService)
+		if err != nil {
+			log.Error("failed to read time entry", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: code, Err: err.Error()})
+			return
+		}
+
+		bind := &dto.TimeEntryRequest{}
+		if err := render.Bind(r, bind); err != nil {
+			log.Error("failed to load request", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		entry, err = timeService.Update(entry.ID, &service.TimeEntryInput{Start: bind.Start, End: bind.End, Note: bind.Note})
+		if err != nil {
+			log.Error("failed to update time entry", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		render.Render(w, r, timeEntryResponce(entry))
+	}
+}
+
+func NewDeleteTimeEntry(log *slog.Logger, timeService service.TimeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "deleteTimeEntry"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		entry, code, err := readTimeEntry(r, timeService)
+		if err != nil {
+			log.Error("failed to read time entry", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: code, Err: err.Error()})
+			return
+		}
+
+		if err := timeService.Delete(entry.ID); err != nil {
+			log.Error("failed to delete time entry", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusInternalServerError, Err: err.Error()})
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+	}
+}
+
+func NewTimeReport(log *slog.Logger, timeService service.TimeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "timeReport"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		values := r.URL.Query()
+		query := &service.TimeReportQuery{
+			From:     values.Get("from"),
+			To:       values.Get("to"),
+			TimeZone: values.Get("tz"),
+		}
+
+		report, err := timeService.Report(fmt.Sprint(r.Context().Value("auth.id")), query)
+		if err != nil {
+			log.Error("failed to report time", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		render.Render(w, r, &dto.TimeReportResponce{
+			From:     report.From,
+			To:       report.To,
+			TimeZone: report.TimeZone,
+			Seconds:  int64(report.Total / time.Second),
+			Days:     timeTotalsResponce(report.Days),
+			Tags:     timeTotalsResponce(report.Tags),
+			Projects: timeTotalsResponce(report.Projects),
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"github.com/turbekoff/todo/internal/delivery/rest/dto"
+	"github.com/turbekoff/todo/internal/domain/repositories"
+	"github.com/turbekoff/todo/internal/service"
+	"golang.org/x/exp/slog"
+)
+
+func NewReadTrash(log *slog.Logger, taskService service.TaskService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "readTrash"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		tasks, err := taskService.ReadTrash(fmt.Sprint(r.Context().Value("auth.id")))
+		if err != nil {
+			log.Error("failed to read trash", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusInternalServerError, Err: err.Error()})
+			return
+		}
+
+		result := dto.TaskListResponce{}
+		for _, task := range tasks {
+			result = append(result, *taskResponce(task))
+		}
+
+		render.Render(w, r, &result)
+	}
+}
+
+func NewRestoreTask(log *slog.Logger, taskService service.TaskService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "restoreTask"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		task, err := taskService.Read(chi.URLParam(r, "id"))
+		if errors.Is(err, repositories.ErrTaskNotFound) {
+			log.Error("failed to read task", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusNotFound, Err: err.Error()})
+			return
+		}
+		if err != nil {
+			log.Error("failed to read task", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusInternalServerError, Err: err.Error()})
+			return
+		}
+
+		if task.Owner != fmt.Sprint(r.Context().Value("auth.id")) {
+			log.Error("failed to read task", slog.Attr{Key: "error", Value: slog.StringValue(ErrTaskAuthorization.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusForbidden, Err: ErrTaskAuthorization.Error()})
+			return
+		}
+
+		task, err = taskService.Restore(task.ID)
+		if errors.Is(err, service.ErrTaskNotTrashed) {
+			log.Error("failed to restore task", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusConflict, Err: err.Error()})
+			return
+		}
+		if err != nil {
+			log.Error("failed to restore task", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusInternalServerError, Err: err.Error()})
+			return
+		}
+
+		w.Header().Set("ETag", etag(task.Version))
+		render.Render(w, r, taskResponce(task))
+	}
+}
@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -51,9 +53,10 @@ func NewProfile(log *slog.Logger, userService service.UserService) http.HandlerF
 		}
 
 		render.Render(w, r, &dto.UserResponce{
-			Name:      user.Name,
-			CreatedAt: user.CreatedAt,
-			UpdatedAt: user.UpdatedAt,
+			Name:            user.Name,
+			AutoArchiveDays: user.AutoArchiveDays,
+			CreatedAt:       user.CreatedAt,
+			UpdatedAt:       user.UpdatedAt,
 		})
 	}
 }
@@ -72,7 +75,7 @@ func NewUpdateProfile(log *slog.Logger, userService service.UserService) http.Ha
 			return
 		}
 
-		user, err := userService.Update(fmt.Sprint(r.Context().Value("auth.id")), bind.Name, bind.Password)
+		user, err := userService.Update(fmt.Sprint(r.Context().Value("auth.id")), &service.UserInput{Name: bind.Name, Password: &bind.Password})
 		if err != nil {
 			log.Error("failed to update user", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
 			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
@@ -80,9 +83,87 @@ func NewUpdateProfile(log *slog.Logger, userService service.UserService) http.Ha
 		}
 
 		render.Render(w, r, &dto.UserResponce{
-			Name:      user.Name,
-			CreatedAt: user.CreatedAt,
-			UpdatedAt: user.UpdatedAt,
+			Name:            user.Name,
+			AutoArchiveDays: user.AutoArchiveDays,
+			CreatedAt:       user.CreatedAt,
+			UpdatedAt:       user.UpdatedAt,
+		})
+	}
+}
+
+func NewPatchProfile(log *slog.Logger, userService service.UserService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "patchProfile"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		user, err := userService.Read(fmt.Sprint(r.Context().Value("auth.id")))
+		if err != nil {
+			log.Error("failed to read user", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusInternalServerError, Err: err.Error()})
+			return
+		}
+
+		document, err := mergePatch(r, &dto.UserPatchRequest{Name: user.Name})
+		if errors.Is(err, ErrPatchMediaType) {
+			log.Error("failed to load request", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusUnsupportedMediaType, Err: err.Error()})
+			return
+		}
+
+		bind := &dto.UserPatchRequest{}
+		if err == nil {
+			err = json.Unmarshal(document, bind)
+		}
+		if err != nil {
+			log.Error("failed to load request", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		user, err = userService.Update(user.ID, &service.UserInput{Name: bind.Name, Password: bind.Password})
+		if err != nil {
+			log.Error("failed to update user", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		render.Render(w, r, &dto.UserResponce{
+			Name:            user.Name,
+			AutoArchiveDays: user.AutoArchiveDays,
+			CreatedAt:       user.CreatedAt,
+			UpdatedAt:       user.UpdatedAt,
+		})
+	}
+}
+
+func NewUpdateAutoArchive(log *slog.Logger, userService service.UserService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "updateAutoArchive"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		bind := &dto.AutoArchiveRequest{}
+		if err := render.Bind(r, bind); err != nil {
+			log.Error("failed to load request", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		user, err := userService.SetAutoArchive(fmt.Sprint(r.Context().Value("auth.id")), bind.AutoArchiveDays)
+		if err != nil {
+			log.Error("failed to update user", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		render.Render(w, r, &dto.UserResponce{
+			Name:            user.Name,
+			AutoArchiveDays: user.AutoArchiveDays,
+			CreatedAt:       user.CreatedAt,
+			UpdatedAt:       user.UpdatedAt,
 		})
 	}
 }
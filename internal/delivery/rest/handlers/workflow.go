@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"github.com/turbekoff/todo/internal/delivery/rest/dto"
+	"github.com/turbekoff/todo/internal/domain/entities"
+	"github.com/turbekoff/todo/internal/service"
+	"golang.org/x/exp/slog"
+)
+
+func workflowStatusResponce(status *entities.WorkflowStatus) dto.WorkflowStatusResponce {
+	next := status.Next
+	if next == nil {
+		next = []string{}
+	}
+	return dto.WorkflowStatusResponce{Key: status.Key, Name: status.Name, Done: status.Done, Next: next}
+}
+
+func workflowResponce(workflow *entities.Workflow) *dto.WorkflowResponce {
+	result := &dto.WorkflowResponce{Statuses: []dto.WorkflowStatusResponce{}}
+	for _, status := range workflow.Statuses {
+		result.Statuses = append(result.Statuses, workflowStatusResponce(status))
+	}
+	return result
+}
+
+func NewReadWorkflow(log *slog.Logger, userService service.UserService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "readWorkflow"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		workflow, err := userService.ReadWorkflow(fmt.Sprint(r.Context().Value("auth.id")))
+		if err != nil {
+			log.Error("failed to read workflow", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusInternalServerError, Err: err.Error()})
+			return
+		}
+
+		render.Render(w, r, workflowResponce(workflow))
+	}
+}
+
+func NewUpdateWorkflow(log *slog.Logger, userService service.UserService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "updateWorkflow"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		bind := &dto.WorkflowRequest{}
+		if err := render.Bind(r, bind); err != nil {
+			log.Error("failed to load request", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		var workflow *entities.Workflow
+		if len(bind.Statuses) > 0 {
+			workflow = &entities.Workflow{}
+			for _, status := range bind.Statuses {
+				workflow.Statuses = append(workflow.Statuses, &entities.WorkflowStatus{
+					Key:  status.Key,
+					Name: status.Name,
+					Done: status.Done,
+					Next: status.Next,
+				})
+			}
+		}
+
+		workflow, err := userService.SetWorkflow(fmt.Sprint(r.Context().Value("auth.id")), workflow)
+		if err != nil {
+			log.Error("failed to update workflow", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		render.Render(w, r, workflowResponce(workflow))
+	}
+}
+
+func NewReadBoard(log *slog.Logger, taskService service.TaskService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("handler", "readBoard"),
+			slog.String("requestID", middleware.GetReqID(r.Context())),
+		)
+
+		values := r.URL.Query()
+		query := &service.BoardQuery{Project: values.Get("project")}
+
+		if limit := values.Get("limit"); limit != "" {
+			var err error
+			if query.Limit, err = strconv.Atoi(limit); err != nil {
+				log.Error("failed to load request", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+				render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: "limit must be a number"})
+				return
+			}
+		}
+
+		board, err := taskService.ReadBoard(fmt.Sprint(r.Context().Value("auth.id")), query)
+		if err != nil {
+			log.Error("failed to read board", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			render.Render(w, r, &dto.ErrResponce{Code: http.StatusBadRequest, Err: err.Error()})
+			return
+		}
+
+		result := &dto.BoardResponce{Columns: []dto.BoardColumnResponce{}}
+		for _, column := range board.Columns {
+			tasks := dto.TaskListResponce{}
+			for _, task := range column.Tasks {
+				tasks = append(tasks, *taskResponce(task))
+			}
+
+			result.Columns = append(result.Columns, dto.BoardColumnResponce{
+				Status: workflowStatusResponce(column.Status),
+				Tasks:  tasks,
+				Total:  column.Total,
+			})
+		}
+
+		render.Render(w, r, result)
+	}
+}
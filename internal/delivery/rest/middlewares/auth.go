@@ -26,7 +26,7 @@ func Auth(sessionService service.SessionService) func(next http.Handler) http.Ha
 				return
 			}
 
-			id, err := sessionService.VerifyAccess(strings.TrimSpace(auth[7:]))
+			id, session, err := sessionService.VerifyAccess(strings.TrimSpace(auth[7:]))
 			if err != nil {
 				render.Render(w, r, &dto.ErrResponce{Code: http.StatusUnauthorized, Err: err.Error()})
 				return
@@ -34,6 +34,7 @@ func Auth(sessionService service.SessionService) func(next http.Handler) http.Ha
 
 			ctx := r.Context()
 			ctx = context.WithValue(ctx, "auth.id", id)
+			ctx = context.WithValue(ctx, "auth.session", session)
 			ctx = context.WithValue(ctx, "auth.device", r.RemoteAddr)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
@@ -15,6 +15,11 @@ func NewRouter(
 	userService service.UserService,
 	taskService service.TaskService,
 	sessionService service.SessionService,
+	projectService service.ProjectService,
+	timeService service.TimeService,
+	templateService service.TemplateService,
+	filterService service.FilterService,
+	commentService service.CommentService,
 ) *chi.Mux {
 	router := chi.NewRouter()
 
@@ -27,20 +32,73 @@ func NewRouter(
 	router.Group(func(r chi.Router) {
 		r.Use(middlewares.Auth(sessionService))
 		r.Post("/api/v1/logout", handlers.NewLogout(log, sessionService))
+		r.Post("/api/v1/logout/all", handlers.NewLogoutAll(log, sessionService))
 		r.Get("/api/v1/profile", handlers.NewProfile(log, userService))
 		r.Put("/api/v1/profile", handlers.NewUpdateProfile(log, userService))
+		r.Patch("/api/v1/profile", handlers.NewPatchProfile(log, userService))
 		r.Delete("/api/v1/profile", handlers.NewDelete(log, userService))
+		r.Put("/api/v1/profile/archive", handlers.NewUpdateAutoArchive(log, userService))
+		r.Get("/api/v1/profile/workflow", handlers.NewReadWorkflow(log, userService))
+		r.Put("/api/v1/profile/workflow", handlers.NewUpdateWorkflow(log, userService))
+
+		r.Get("/api/v1/sessions", handlers.NewReadSessions(log, sessionService))
+		r.Delete("/api/v1/sessions/{id}", handlers.NewDeleteSession(log, sessionService))
 
 		r.Post("/api/v1/task", handlers.NewCreateTask(log, taskService))
 		r.Get("/api/v1/task", handlers.NewReadTasks(log, taskService))
+		r.Get("/api/v1/task/search", handlers.NewSearchTasks(log, taskService))
+		r.Post("/api/v1/task/quick", handlers.NewQuickAddTask(log, taskService, projectService))
+		r.Post("/api/v1/task/archive", handlers.NewArchiveTasks(log, taskService))
+		r.Post("/api/v1/task/batch", handlers.NewBatchTasks(log, taskService))
 		r.Get("/api/v1/task/{id}", handlers.NewReadTask(log, taskService))
 		r.Put("/api/v1/task/{id}", handlers.NewUpdateTask(log, taskService))
+		r.Patch("/api/v1/task/{id}", handlers.NewPatchTask(log, taskService))
 		r.Delete("/api/v1/task/{id}", handlers.NewDeleteTask(log, taskService))
+		r.Get("/api/v1/task/{id}/dependencies", handlers.NewReadDependencies(log, taskService))
+		r.Post("/api/v1/task/{id}/move", handlers.NewMoveTask(log, taskService))
+		r.Post("/api/v1/task/{id}/restore", handlers.NewRestoreTask(log, taskService))
+		r.Get("/api/v1/task/{id}/time", handlers.NewReadTimeEntries(log, taskService, timeService))
+		r.Post("/api/v1/task/{id}/time", handlers.NewCreateTimeEntry(log, taskService, timeService))
+		r.Post("/api/v1/task/{id}/time/start", handlers.NewStartTimer(log, taskService, timeService))
+		r.Post("/api/v1/task/{id}/time/stop", handlers.NewStopTimer(log, taskService, timeService))
+		r.Put("/api/v1/task/{id}/time/{entry}", handlers.NewUpdateTimeEntry(log, timeService))
+		r.Delete("/api/v1/task/{id}/time/{entry}", handlers.NewDeleteTimeEntry(log, timeService))
+		r.Get("/api/v1/task/{id}/comments", handlers.NewReadComments(log, taskService, commentService))
+		r.Post("/api/v1/task/{id}/comments", handlers.NewCreateComment(log, commentService))
+		r.Put("/api/v1/task/{id}/comments/{comment}", handlers.NewUpdateComment(log, commentService))
+		r.Delete("/api/v1/task/{id}/comments/{comment}", handlers.NewDeleteComment(log, commentService))
+		r.Get("/api/v1/trash", handlers.NewReadTrash(log, taskService))
+
+		r.Get("/api/v1/tags", handlers.NewReadTags(log, taskService))
+		r.Get("/api/v1/board", handlers.NewReadBoard(log, taskService))
+
+		r.Get("/api/v1/reports/time", handlers.NewTimeReport(log, timeService))
+
+		r.Post("/api/v1/projects", handlers.NewCreateProject(log, projectService))
+		r.Get("/api/v1/projects", handlers.NewReadProjects(log, projectService))
+		r.Get("/api/v1/projects/{id}", handlers.NewReadProject(log, projectService))
+		r.Put("/api/v1/projects/{id}", handlers.NewUpdateProject(log, projectService))
+		r.Delete("/api/v1/projects/{id}", handlers.NewDeleteProject(log, projectService))
+
+		r.Post("/api/v1/templates", handlers.NewCreateTemplate(log, templateService))
+		r.Get("/api/v1/templates", handlers.NewReadTemplates(log, templateService))
+		r.Get("/api/v1/templates/{id}", handlers.NewReadTemplate(log, templateService))
+		r.Put("/api/v1/templates/{id}", handlers.NewUpdateTemplate(log, templateService))
+		r.Delete("/api/v1/templates/{id}", handlers.NewDeleteTemplate(log, templateService))
+		r.Post("/api/v1/templates/{id}/instantiate", handlers.NewInstantiateTemplate(log, templateService))
+
+		r.Post("/api/v1/filters", handlers.NewCreateFilter(log, filterService))
+		r.Get("/api/v1/filters", handlers.NewReadFilters(log, filterService))
+		r.Get("/api/v1/filters/{id}", handlers.NewReadFilter(log, filterService))
+		r.Put("/api/v1/filters/{id}", handlers.NewUpdateFilter(log, filterService))
+		r.Delete("/api/v1/filters/{id}", handlers.NewDeleteFilter(log, filterService))
+		r.Get("/api/v1/filters/{id}/tasks", handlers.NewReadFilterTasks(log, filterService))
 	})
 
 	router.Group(func(r chi.Router) {
 		r.Post("/api/v1/signup", handlers.NewSignup(log, userService))
 		r.Post("/api/v1/signin", handlers.NewSignin(log, sessionService))
+		r.Post("/api/v1/refresh", handlers.NewRefresh(log, sessionService))
 	})
 
 	return router
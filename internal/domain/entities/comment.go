@@ -0,0 +1,12 @@
+package entities
+
+import "time"
+
+type Comment struct {
+	ID        string
+	Task      string
+	Author    string
+	Body      string
+	CreatedAt time.Time
+	EditedAt  *time.Time
+}
@@ -0,0 +1,13 @@
+package entities
+
+import "time"
+
+type Filter struct {
+	ID        string
+	Owner     string
+	Name      string
+	Query     string
+	TimeZone  string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,36 @@
+package entities
+
+import "errors"
+
+// Priority is stored as a level, so tasks can be sorted by it.
+type Priority int
+
+const (
+	PriorityNone Priority = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+	PriorityUrgent
+)
+
+var priorityNames = []string{"none", "low", "medium", "high", "urgent"}
+
+func (p Priority) String() string {
+	if p < PriorityNone || p > PriorityUrgent {
+		return priorityNames[PriorityNone]
+	}
+	return priorityNames[p]
+}
+
+func ParsePriority(s string) (Priority, error) {
+	if s == "" {
+		return PriorityNone, nil
+	}
+
+	for level, name := range priorityNames {
+		if name == s {
+			return Priority(level), nil
+		}
+	}
+	return PriorityNone, errors.New("priority must be one of none, low, medium, high or urgent")
+}
@@ -0,0 +1,14 @@
+package entities
+
+import "time"
+
+type Project struct {
+	ID        string
+	Owner     string
+	Name      string
+	Color     string
+	Position  int
+	Archived  bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -3,9 +3,13 @@ package entities
 import "time"
 
 type Session struct {
-	ID       string
-	Owner    string
-	Device   string
-	Token    string
-	ExpireAt time.Time
+	ID            string
+	Owner         string
+	Device        string
+	UserAgent     string
+	Token         string
+	PreviousToken string
+	CreatedAt     time.Time
+	LastUsedAt    time.Time
+	ExpireAt      time.Time
 }
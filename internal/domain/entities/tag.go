@@ -0,0 +1,7 @@
+package entities
+
+// TagUsage tells how many tasks of an owner are tagged with the tag.
+type TagUsage struct {
+	Name  string
+	Count int
+}
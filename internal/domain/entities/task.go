@@ -6,9 +6,34 @@ import (
 
 type Task struct {
 	ID        string
+	Version   int64
 	Owner     string
+	Project   string
+	Parent    string
 	Name      string
+	Notes     string
+	Status    string
 	Completed bool
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	Archived  bool
+	Priority  Priority
+	Tags      []string
+	DueDate   string
+	DueTime   string
+	TimeZone  string
+	DueAt     *time.Time
+
+	// Tasks created before ordering have no Position.
+	Position string
+
+	// Blocked is set while any of BlockedBy is neither completed nor trashed.
+	BlockedBy []string
+	Blocked   bool
+
+	Recurrence          string
+	RecurFromCompletion bool
+
+	CompletedAt *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   *time.Time
 }
@@ -0,0 +1,23 @@
+package entities
+
+import "time"
+
+type Template struct {
+	ID        string
+	Owner     string
+	Name      string
+	Tasks     []*TemplateTask
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// DueOffset counts days from the instantiation, nil for no due date.
+type TemplateTask struct {
+	Name      string
+	Notes     string
+	Priority  Priority
+	Tags      []string
+	DueOffset *int
+	DueTime   string
+	Subtasks  []*TemplateTask
+}
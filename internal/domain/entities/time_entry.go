@@ -0,0 +1,15 @@
+package entities
+
+import "time"
+
+// End is nil while the timer is running.
+type TimeEntry struct {
+	ID        string
+	Owner     string
+	Task      string
+	Start     time.Time
+	End       *time.Time
+	Note      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
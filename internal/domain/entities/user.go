@@ -3,9 +3,13 @@ package entities
 import "time"
 
 type User struct {
-	ID        string
-	Name      string
-	Password  string
+	ID       string
+	Name     string
+	Password string
+	// Zero turns auto archiving off.
+	AutoArchiveDays int
+	// Nil stands for the DefaultWorkflow.
+	Workflow  *Workflow
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
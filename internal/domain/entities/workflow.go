@@ -0,0 +1,84 @@
+package entities
+
+const (
+	StatusTodo       = "todo"
+	StatusInProgress = "inProgress"
+	StatusReview     = "review"
+	StatusDone       = "done"
+)
+
+// An empty Next allows every status.
+type WorkflowStatus struct {
+	Key  string
+	Name string
+	Done bool
+	Next []string
+}
+
+// A Workflow has at least one done status and one that isn't.
+type Workflow struct {
+	Statuses []*WorkflowStatus
+}
+
+func DefaultWorkflow() *Workflow {
+	return &Workflow{Statuses: []*WorkflowStatus{
+		{Key: StatusTodo, Name: "To do", Next: []string{StatusInProgress, StatusDone}},
+		{Key: StatusInProgress, Name: "In progress", Next: []string{StatusTodo, StatusReview, StatusDone}},
+		{Key: StatusReview, Name: "In review", Next: []string{StatusInProgress, StatusDone}},
+		{Key: StatusDone, Name: "Done", Done: true, Next: []string{StatusTodo}},
+	}}
+}
+
+func (w *Workflow) Status(key string) *WorkflowStatus {
+	for _, status := range w.Statuses {
+		if status.Key == key {
+			return status
+		}
+	}
+	return nil
+}
+
+func (w *Workflow) Initial() *WorkflowStatus {
+	for _, status := range w.Statuses {
+		if !status.Done {
+			return status
+		}
+	}
+	return nil
+}
+
+func (w *Workflow) Final() *WorkflowStatus {
+	for _, status := range w.Statuses {
+		if status.Done {
+			return status
+		}
+	}
+	return nil
+}
+
+// A status missing from the workflow falls back to the initial or the final
+// status by the completion of the task.
+func (w *Workflow) Resolve(key string, completed bool) *WorkflowStatus {
+	if status := w.Status(key); status != nil && status.Done == completed {
+		return status
+	}
+
+	if completed {
+		return w.Final()
+	}
+	return w.Initial()
+}
+
+func (w *Workflow) CanMove(from, to string) bool {
+	status := w.Status(from)
+	if from == to || status == nil || len(status.Next) == 0 {
+		return true
+	}
+
+	for _, next := range status.Next {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,14 @@
+package repositories
+
+import "github.com/turbekoff/todo/internal/domain/entities"
+
+type CommentRepository interface {
+	Create(comment *entities.Comment) error
+	Read(id string) (*entities.Comment, error)
+	// ReadAllByTask lists the comments of the task, the oldest first.
+	ReadAllByTask(task string) ([]*entities.Comment, error)
+	Update(comment *entities.Comment) error
+	Delete(id string) error
+	DeleteAllByAuthor(author string) error
+	DeleteAllByTask(ids []string) error
+}
@@ -5,5 +5,16 @@ import "errors"
 var (
 	ErrUserNotFound    = errors.New("user doesn't exists")
 	ErrSessionNotFound = errors.New("session doesn't exists")
+	ErrSessionConflict = errors.New("session has been modified by another request")
 	ErrTaskNotFound    = errors.New("task doesn't exists")
+	ErrProjectNotFound = errors.New("project doesn't exists")
+	ErrInvalidCursor   = errors.New("invalid cursor")
+	ErrTaskConflict    = errors.New("task has been modified by another request")
+
+	ErrTimeEntryNotFound = errors.New("time entry doesn't exists")
+	ErrTimerRunning      = errors.New("another timer is already running")
+
+	ErrTemplateNotFound = errors.New("template doesn't exists")
+	ErrFilterNotFound   = errors.New("filter doesn't exists")
+	ErrCommentNotFound  = errors.New("comment doesn't exists")
 )
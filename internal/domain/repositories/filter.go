@@ -0,0 +1,13 @@
+package repositories
+
+import "github.com/turbekoff/todo/internal/domain/entities"
+
+type FilterRepository interface {
+	Create(filter *entities.Filter) error
+	Read(id string) (*entities.Filter, error)
+	// ReadAllByOwner lists the filters of the owner ordered by name.
+	ReadAllByOwner(owner string) ([]*entities.Filter, error)
+	Update(filter *entities.Filter) error
+	Delete(id string) error
+	DeleteAllByOwner(owner string) error
+}
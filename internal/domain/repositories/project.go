@@ -0,0 +1,11 @@
+package repositories
+
+import "github.com/turbekoff/todo/internal/domain/entities"
+
+type ProjectRepository interface {
+	Create(project *entities.Project) error
+	Read(id string) (*entities.Project, error)
+	ReadAllByOwner(owner string) ([]*entities.Project, error)
+	Update(project *entities.Project) error
+	Delete(id string) error
+}
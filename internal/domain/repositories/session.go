@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"time"
+
 	"github.com/turbekoff/todo/internal/domain/entities"
 )
 
@@ -8,7 +10,12 @@ type SessionRepository interface {
 	Create(session *entities.Session) error
 	Read(id string) (*entities.Session, error)
 	ReadByToken(token string) (*entities.Session, error)
+	ReadByPreviousToken(token string) (*entities.Session, error)
 	ReadAllByOwner(owner string) ([]*entities.Session, error)
 	Update(session *entities.Session) error
+	// Rotate fails with ErrSessionConflict when the token is no longer the previous
+	// one.
+	Rotate(session *entities.Session) error
+	Touch(id string, at time.Time) error
 	Delete(id string) error
 }
@@ -1,11 +1,134 @@
 package repositories
 
-import "github.com/turbekoff/todo/internal/domain/entities"
+import (
+	"time"
+
+	"github.com/turbekoff/todo/internal/domain/entities"
+)
+
+// Tasks in the trash never match a TaskFilter, archived tasks only match when
+// Archived is set.
+type TaskFilter struct {
+	Owner      string
+	Project    *string
+	Parent     *string
+	Completed  *bool
+	DueBefore  *time.Time
+	DueFrom    string
+	DueTo      string
+	WithoutDue bool
+	Tags       []string
+	AllTags    bool
+	Archived   bool
+	Blocked    *bool
+	Condition  *TaskCondition
+}
+
+const (
+	ConditionAnd = "and"
+	ConditionOr  = "or"
+	ConditionNot = "not"
+
+	ConditionEqual          = "eq"
+	ConditionLess           = "lt"
+	ConditionLessOrEqual    = "lte"
+	ConditionGreater        = "gt"
+	ConditionGreaterOrEqual = "gte"
+	// ConditionEmpty matches empty strings as well, ConditionContains ignores case.
+	ConditionEmpty    = "empty"
+	ConditionContains = "contains"
+)
+
+const (
+	TaskFieldName      = "name"
+	TaskFieldProject   = "project"
+	TaskFieldStatus    = "status"
+	TaskFieldCompleted = "completed"
+	TaskFieldBlocked   = "blocked"
+	TaskFieldPriority  = "priority"
+	TaskFieldTags      = "tags"
+	TaskFieldDueDate   = "dueDate"
+	TaskFieldDueAt     = "dueAt"
+)
+
+// An empty project of a TaskCondition stands for the tasks without one.
+type TaskCondition struct {
+	Op         string
+	Conditions []*TaskCondition
+	Field      string
+	Value      interface{}
+}
+
+const (
+	TaskSortPriority  = "priority"
+	TaskSortCreatedAt = "createdAt"
+	TaskSortUpdatedAt = "updatedAt"
+	TaskSortDueAt     = "dueAt"
+	TaskSortName      = "name"
+	TaskSortPosition  = "position"
+)
+
+type TaskSort struct {
+	Field      string
+	Descending bool
+}
+
+type Page struct {
+	Limit     int
+	Cursor    string
+	WithTotal bool
+}
+
+type TaskPage struct {
+	Tasks      []*entities.Task
+	NextCursor string
+	Total      *int64
+}
+
+// Empty fields of a TaskChange are left untouched.
+type TaskChange struct {
+	Completed *bool
+	Status    string
+	Project   *string
+	AddTag    string
+	At        time.Time
+}
 
 type TaskRepository interface {
 	Create(task *entities.Task) error
 	Read(id string) (*entities.Task, error)
+	ReadAll(filter *TaskFilter, sort *TaskSort) ([]*entities.Task, error)
+	ReadPage(filter *TaskFilter, sort *TaskSort, page *Page) (*TaskPage, error)
+	// ReadAllByOwner includes the tasks in the trash.
 	ReadAllByOwner(owner string) ([]*entities.Task, error)
+	// ReadMany skips unknown ids and the tasks in the trash, ReadDependents
+	// includes them.
+	ReadMany(owner string, ids []string) ([]*entities.Task, error)
+	ReadAllByParent(parent string) ([]*entities.Task, error)
+	ReadDependents(ids []string) ([]*entities.Task, error)
+	// SetBlocked ignores the version of the task.
+	SetBlocked(id string, blocked bool) error
+	ReadTags(owner string) ([]*entities.TagUsage, error)
+	// An empty position stands for the end of the list before and for its start
+	// after. Trashed tasks and ones without a position are skipped, ties are
+	// ordered by id.
+	ReadAdjacent(owner, position string, before bool, exclude string) (*entities.Task, error)
+	Search(owner, text string, archived bool, limit int) ([]*entities.Task, error)
+	// Update fails with ErrTaskConflict when the task changed since it was read.
 	Update(task *entities.Task) error
-	Delete(id string) error
+	UpdateMany(owner string, ids []string, change *TaskChange) error
+	MoveAllByProject(project, to string) error
+	// Tasks completed before completion times were recorded are archived by
+	// their last update.
+	Archive(owner string, completedBefore time.Time) (int64, error)
+	ReadTrash(owner string) ([]*entities.Task, error)
+	Trash(id string, at time.Time) error
+	TrashMany(owner string, ids []string, at time.Time) error
+	// TrashAllByProject, Purge and Delete return the ids of the tasks, subtasks
+	// included.
+	TrashAllByProject(project string, at time.Time) ([]string, error)
+	// Restore brings back the subtasks trashed along with the task.
+	Restore(id string) error
+	Purge(before time.Time) ([]string, error)
+	Delete(id string) ([]string, error)
 }
@@ -0,0 +1,13 @@
+package repositories
+
+import "github.com/turbekoff/todo/internal/domain/entities"
+
+type TemplateRepository interface {
+	Create(template *entities.Template) error
+	Read(id string) (*entities.Template, error)
+	// ReadAllByOwner lists the templates of the owner ordered by name.
+	ReadAllByOwner(owner string) ([]*entities.Template, error)
+	Update(template *entities.Template) error
+	Delete(id string) error
+	DeleteAllByOwner(owner string) error
+}
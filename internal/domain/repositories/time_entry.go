@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/turbekoff/todo/internal/domain/entities"
+)
+
+type TimeEntryRepository interface {
+	// Create and Update fail with ErrTimerRunning on a second running entry.
+	Create(entry *entities.TimeEntry) error
+	Read(id string) (*entities.TimeEntry, error)
+	ReadAllByTask(task string) ([]*entities.TimeEntry, error)
+	ReadRunning(owner string) (*entities.TimeEntry, error)
+	// ReadAllByPeriod includes running entries.
+	ReadAllByPeriod(owner string, from, to time.Time) ([]*entities.TimeEntry, error)
+	Update(entry *entities.TimeEntry) error
+	Delete(id string) error
+	DeleteAllByOwner(owner string) error
+	DeleteAllByTask(ids []string) error
+}
@@ -6,6 +6,7 @@ type UserRepository interface {
 	Create(user *entities.User) error
 	Read(id string) (*entities.User, error)
 	ReadByName(name string) (*entities.User, error)
+	ReadAllWithAutoArchive() ([]*entities.User, error)
 	Update(user *entities.User) error
 	Delete(id string) error
 }
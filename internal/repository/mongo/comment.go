@@ -0,0 +1,103 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/turbekoff/todo/internal/domain/entities"
+	"github.com/turbekoff/todo/internal/domain/repositories"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CommentRepository struct {
+	db *mongo.Collection
+}
+
+func NewCommentRepository(db *mongo.Database) repositories.CommentRepository {
+	return &CommentRepository{db: db.Collection("comments")}
+}
+
+func (r *CommentRepository) Create(comment *entities.Comment) error {
+	model := toCommentModel(comment)
+	if model.ID.IsZero() {
+		model.ID = primitive.NewObjectID()
+	}
+
+	if _, err := r.db.InsertOne(context.Background(), model); err != nil {
+		return err
+	}
+
+	comment.ID = model.ID.Hex()
+	return nil
+}
+
+func (r *CommentRepository) Read(id string) (*entities.Comment, error) {
+	objectID, _ := primitive.ObjectIDFromHex(id)
+
+	var comment Comment
+	if err := r.db.FindOne(context.Background(), bson.M{"_id": objectID}).Decode(&comment); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, repositories.ErrCommentNotFound
+		}
+		return nil, err
+	}
+	return toCommentEntity(&comment), nil
+}
+
+func (r *CommentRepository) ReadAllByTask(task string) ([]*entities.Comment, error) {
+	objectID, _ := primitive.ObjectIDFromHex(task)
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.db.Find(context.Background(), bson.M{"task": objectID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var comments []Comment
+	if err = cursor.All(context.TODO(), &comments); err != nil {
+		return nil, err
+	}
+
+	var entities []*entities.Comment
+	for _, comment := range comments {
+		entities = append(entities, toCommentEntity(&comment))
+	}
+
+	return entities, nil
+}
+
+func (r *CommentRepository) Update(comment *entities.Comment) error {
+	model := toCommentModel(comment)
+	query := bson.M{}
+	query["body"] = model.Body
+	query["editedAt"] = model.EditedAt
+
+	_, err := r.db.UpdateOne(context.Background(), bson.M{"_id": model.ID}, bson.M{"$set": query})
+	return err
+}
+
+func (r *CommentRepository) Delete(id string) error {
+	objectID, _ := primitive.ObjectIDFromHex(id)
+
+	_, err := r.db.DeleteOne(context.Background(), bson.M{"_id": objectID})
+	return err
+}
+
+func (r *CommentRepository) DeleteAllByAuthor(author string) error {
+	objectID, _ := primitive.ObjectIDFromHex(author)
+
+	_, err := r.db.DeleteMany(context.Background(), bson.M{"author": objectID})
+	return err
+}
+
+func (r *CommentRepository) DeleteAllByTask(ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	_, err := r.db.DeleteMany(context.Background(), bson.M{"task": bson.M{"$in": objectIDs(ids)}})
+	return err
+}
@@ -0,0 +1,96 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/turbekoff/todo/internal/domain/entities"
+	"github.com/turbekoff/todo/internal/domain/repositories"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type FilterRepository struct {
+	db *mongo.Collection
+}
+
+func NewFilterRepository(db *mongo.Database) repositories.FilterRepository {
+	return &FilterRepository{db: db.Collection("filters")}
+}
+
+func (r *FilterRepository) Create(filter *entities.Filter) error {
+	model := toFilterModel(filter)
+	if model.ID.IsZero() {
+		model.ID = primitive.NewObjectID()
+	}
+
+	if _, err := r.db.InsertOne(context.Background(), model); err != nil {
+		return err
+	}
+
+	filter.ID = model.ID.Hex()
+	return nil
+}
+
+func (r *FilterRepository) Read(id string) (*entities.Filter, error) {
+	objectID, _ := primitive.ObjectIDFromHex(id)
+
+	var filter Filter
+	if err := r.db.FindOne(context.Background(), bson.M{"_id": objectID}).Decode(&filter); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, repositories.ErrFilterNotFound
+		}
+		return nil, err
+	}
+	return toFilterEntity(&filter), nil
+}
+
+func (r *FilterRepository) ReadAllByOwner(owner string) ([]*entities.Filter, error) {
+	objectID, _ := primitive.ObjectIDFromHex(owner)
+
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.db.Find(context.Background(), bson.M{"owner": objectID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var filters []Filter
+	if err = cursor.All(context.TODO(), &filters); err != nil {
+		return nil, err
+	}
+
+	var entities []*entities.Filter
+	for _, filter := range filters {
+		entities = append(entities, toFilterEntity(&filter))
+	}
+
+	return entities, nil
+}
+
+func (r *FilterRepository) Update(filter *entities.Filter) error {
+	model := toFilterModel(filter)
+	query := bson.M{}
+	query["name"] = model.Name
+	query["query"] = model.Query
+	query["timeZone"] = model.TimeZone
+	query["updatedAt"] = model.UpdatedAt
+
+	_, err := r.db.UpdateOne(context.Background(), bson.M{"_id": model.ID}, bson.M{"$set": query})
+	return err
+}
+
+func (r *FilterRepository) Delete(id string) error {
+	objectID, _ := primitive.ObjectIDFromHex(id)
+
+	_, err := r.db.DeleteOne(context.Background(), bson.M{"_id": objectID})
+	return err
+}
+
+func (r *FilterRepository) DeleteAllByOwner(owner string) error {
+	objectID, _ := primitive.ObjectIDFromHex(owner)
+
+	_, err := r.db.DeleteMany(context.Background(), bson.M{"owner": objectID})
+	return err
+}
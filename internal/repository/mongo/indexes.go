@@ -0,0 +1,129 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// The text index stems no language. A collection holds a single text index, so
+// previous versions are dropped first.
+const taskSearchIndex = "search_notes"
+
+var legacyTaskIndexes = []string{"search"}
+
+func dropIndexes(ctx context.Context, collection *mongo.Collection, names ...string) error {
+	for _, name := range names {
+		_, err := collection.Indexes().DropOne(ctx, name)
+
+		var cmdErr mongo.CommandError
+		if errors.As(err, &cmdErr) && cmdErr.Code == 27 {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ownerIndex(field string) mongo.IndexModel {
+	return mongo.IndexModel{Keys: bson.D{{Key: "owner", Value: 1}, {Key: field, Value: 1}, {Key: "_id", Value: 1}}}
+}
+
+func CreateIndexes(db *mongo.Database) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := dropIndexes(ctx, db.Collection("tasks"), legacyTaskIndexes...); err != nil {
+		return err
+	}
+
+	_, err := db.Collection("tasks").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		ownerIndex("priority"),
+		ownerIndex("createdAt"),
+		ownerIndex("updatedAt"),
+		ownerIndex("dueAt"),
+		ownerIndex("name"),
+		ownerIndex("position"),
+		{Keys: bson.D{{Key: "owner", Value: 1}, {Key: "dueDate", Value: 1}}},
+		{Keys: bson.D{{Key: "owner", Value: 1}, {Key: "tags", Value: 1}}},
+		{Keys: bson.D{{Key: "project", Value: 1}}},
+		{Keys: bson.D{{Key: "parent", Value: 1}}},
+		{Keys: bson.D{{Key: "blockedBy", Value: 1}}},
+		ownerIndex("deletedAt"),
+		{Keys: bson.D{{Key: "deletedAt", Value: 1}}, Options: options.Index().SetSparse(true)},
+		{
+			Keys: bson.D{{Key: "owner", Value: 1}, {Key: "name", Value: "text"}, {Key: "notes", Value: "text"}},
+			Options: options.Index().
+				SetName(taskSearchIndex).
+				SetDefaultLanguage("none").
+				SetWeights(bson.M{"name": 5, "notes": 1}),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection("projects").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "owner", Value: 1}, {Key: "position", Value: 1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection("sessions").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "token", Value: 1}}},
+		{Keys: bson.D{{Key: "previousToken", Value: 1}}, Options: options.Index().SetSparse(true)},
+		{Keys: bson.D{{Key: "owner", Value: 1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection("users").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "autoArchiveDays", Value: 1}}, Options: options.Index().SetSparse(true)},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection("timeEntries").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "owner", Value: 1}},
+			Options: options.Index().
+				SetName("running").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"running": true}),
+		},
+		{Keys: bson.D{{Key: "task", Value: 1}, {Key: "start", Value: -1}}},
+		{Keys: bson.D{{Key: "owner", Value: 1}, {Key: "start", Value: 1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection("templates").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "owner", Value: 1}, {Key: "name", Value: 1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection("filters").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "owner", Value: 1}, {Key: "name", Value: 1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection("comments").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "task", Value: 1}, {Key: "createdAt", Value: 1}}},
+		{Keys: bson.D{{Key: "author", Value: 1}}},
+	})
+	return err
+}
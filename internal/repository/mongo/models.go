@@ -8,48 +8,197 @@ import (
 )
 
 type User struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty"`
+	Name            string             `bson:"name"`
+	Password        string             `bson:"password"`
+	AutoArchiveDays int                `bson:"autoArchiveDays,omitempty"`
+	Workflow        []WorkflowStatus   `bson:"workflow,omitempty"`
+	CreatedAt       time.Time          `bson:"createdAt"`
+	UpdatedAt       time.Time          `bson:"updatedAt"`
+}
+
+type WorkflowStatus struct {
+	Key  string   `bson:"key"`
+	Name string   `bson:"name"`
+	Done bool     `bson:"done,omitempty"`
+	Next []string `bson:"next,omitempty"`
+}
+
+type Session struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty"`
+	Owner         primitive.ObjectID `bson:"owner"`
+	Device        string             `bson:"device"`
+	UserAgent     string             `bson:"userAgent"`
+	Token         string             `bson:"token"`
+	PreviousToken string             `bson:"previousToken,omitempty"`
+	CreatedAt     time.Time          `bson:"createdAt"`
+	LastUsedAt    time.Time          `bson:"lastUsedAt"`
+	ExpireAt      time.Time          `bson:"expireAt"`
+}
+
+type Task struct {
+	ID        primitive.ObjectID  `bson:"_id,omitempty"`
+	Version   int64               `bson:"version"`
+	Owner     primitive.ObjectID  `bson:"owner"`
+	Project   *primitive.ObjectID `bson:"project,omitempty"`
+	Parent    *primitive.ObjectID `bson:"parent,omitempty"`
+	Name      string              `bson:"name"`
+	Notes     string              `bson:"notes,omitempty"`
+	Status    string              `bson:"status,omitempty"`
+	Completed bool                `bson:"completed"`
+	Archived  bool                `bson:"archived,omitempty"`
+	Priority  int                 `bson:"priority"`
+	Tags      []string            `bson:"tags,omitempty"`
+	DueDate   string              `bson:"dueDate,omitempty"`
+	DueTime   string              `bson:"dueTime,omitempty"`
+	TimeZone  string              `bson:"timeZone,omitempty"`
+	DueAt     *time.Time          `bson:"dueAt,omitempty"`
+	Position  string              `bson:"position,omitempty"`
+
+	Recurrence          string `bson:"recurrence,omitempty"`
+	RecurFromCompletion bool   `bson:"recurFromCompletion,omitempty"`
+
+	BlockedBy []primitive.ObjectID `bson:"blockedBy,omitempty"`
+	Blocked   bool                 `bson:"blocked,omitempty"`
+
+	CompletedAt *time.Time `bson:"completedAt,omitempty"`
+	CreatedAt   time.Time  `bson:"createdAt"`
+	UpdatedAt   time.Time  `bson:"updatedAt"`
+	DeletedAt   *time.Time `bson:"deletedAt,omitempty"`
+}
+
+// A unique partial index on Running keeps a single timer of the owner running.
+type TimeEntry struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	Owner     primitive.ObjectID `bson:"owner"`
+	Task      primitive.ObjectID `bson:"task"`
+	Start     time.Time          `bson:"start"`
+	End       *time.Time         `bson:"end,omitempty"`
+	Running   bool               `bson:"running,omitempty"`
+	Note      string             `bson:"note,omitempty"`
+	CreatedAt time.Time          `bson:"createdAt"`
+	UpdatedAt time.Time          `bson:"updatedAt"`
+}
+
+type Template struct {
 	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	Owner     primitive.ObjectID `bson:"owner"`
 	Name      string             `bson:"name"`
-	Password  string             `bson:"password"`
+	Tasks     []TemplateTask     `bson:"tasks"`
 	CreatedAt time.Time          `bson:"createdAt"`
 	UpdatedAt time.Time          `bson:"updatedAt"`
 }
 
-type Session struct {
-	ID       primitive.ObjectID `bson:"_id,omitempty"`
-	Owner    primitive.ObjectID `bson:"owner"`
-	Device   string             `bson:"device"`
-	Token    string             `bson:"token"`
-	ExpireAt time.Time          `bson:"expireAt"`
+type TemplateTask struct {
+	Name      string         `bson:"name"`
+	Notes     string         `bson:"notes,omitempty"`
+	Priority  int            `bson:"priority,omitempty"`
+	Tags      []string       `bson:"tags,omitempty"`
+	DueOffset *int           `bson:"dueOffset,omitempty"`
+	DueTime   string         `bson:"dueTime,omitempty"`
+	Subtasks  []TemplateTask `bson:"subtasks,omitempty"`
 }
 
-type Task struct {
+type Filter struct {
 	ID        primitive.ObjectID `bson:"_id,omitempty"`
 	Owner     primitive.ObjectID `bson:"owner"`
 	Name      string             `bson:"name"`
-	Completed bool               `bson:"completed"`
+	Query     string             `bson:"query"`
+	TimeZone  string             `bson:"timeZone,omitempty"`
 	CreatedAt time.Time          `bson:"createdAt"`
 	UpdatedAt time.Time          `bson:"updatedAt"`
 }
 
+type Comment struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	Task      primitive.ObjectID `bson:"task"`
+	Author    primitive.ObjectID `bson:"author"`
+	Body      string             `bson:"body"`
+	CreatedAt time.Time          `bson:"createdAt"`
+	EditedAt  *time.Time         `bson:"editedAt,omitempty"`
+}
+
+type Project struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	Owner     primitive.ObjectID `bson:"owner"`
+	Name      string             `bson:"name"`
+	Color     string             `bson:"color"`
+	Position  int                `bson:"position"`
+	Archived  bool               `bson:"archived"`
+	CreatedAt time.Time          `bson:"createdAt"`
+	UpdatedAt time.Time          `bson:"updatedAt"`
+}
+
+func toReference(id string) *primitive.ObjectID {
+	if id == "" {
+		return nil
+	}
+
+	objectID, _ := primitive.ObjectIDFromHex(id)
+	return &objectID
+}
+
+func fromReference(id *primitive.ObjectID) string {
+	if id == nil {
+		return ""
+	}
+	return id.Hex()
+}
+
+func fromReferences(ids []primitive.ObjectID) []string {
+	var result []string
+	for _, id := range ids {
+		result = append(result, id.Hex())
+	}
+	return result
+}
+
+func toWorkflowModel(entity *entities.Workflow) []WorkflowStatus {
+	if entity == nil {
+		return nil
+	}
+
+	var statuses []WorkflowStatus
+	for _, status := range entity.Statuses {
+		statuses = append(statuses, WorkflowStatus{Key: status.Key, Name: status.Name, Done: status.Done, Next: status.Next})
+	}
+	return statuses
+}
+
+func toWorkflowEntity(model []WorkflowStatus) *entities.Workflow {
+	if len(model) == 0 {
+		return nil
+	}
+
+	workflow := &entities.Workflow{}
+	for _, status := range model {
+		workflow.Statuses = append(workflow.Statuses, &entities.WorkflowStatus{Key: status.Key, Name: status.Name, Done: status.Done, Next: status.Next})
+	}
+	return workflow
+}
+
 func toUserModel(entity *entities.User) *User {
 	id, _ := primitive.ObjectIDFromHex(entity.ID)
 	return &User{
-		ID:        id,
-		Name:      entity.Name,
-		Password:  entity.Password,
-		CreatedAt: entity.CreatedAt,
-		UpdatedAt: entity.UpdatedAt,
+		ID:              id,
+		Name:            entity.Name,
+		Password:        entity.Password,
+		AutoArchiveDays: entity.AutoArchiveDays,
+		Workflow:        toWorkflowModel(entity.Workflow),
+		CreatedAt:       entity.CreatedAt,
+		UpdatedAt:       entity.UpdatedAt,
 	}
 }
 
 func toUserEntity(model *User) *entities.User {
 	return &entities.User{
-		ID:        model.ID.Hex(),
-		Name:      model.Name,
-		Password:  model.Password,
-		CreatedAt: model.CreatedAt,
-		UpdatedAt: model.UpdatedAt,
+		ID:              model.ID.Hex(),
+		Name:            model.Name,
+		Password:        model.Password,
+		AutoArchiveDays: model.AutoArchiveDays,
+		Workflow:        toWorkflowEntity(model.Workflow),
+		CreatedAt:       model.CreatedAt,
+		UpdatedAt:       model.UpdatedAt,
 	}
 }
 
@@ -57,21 +206,42 @@ func toSessionModel(entity *entities.Session) *Session {
 	id, _ := primitive.ObjectIDFromHex(entity.ID)
 	owner, _ := primitive.ObjectIDFromHex(entity.Owner)
 	return &Session{
-		ID:       id,
-		Owner:    owner,
-		Device:   entity.Device,
-		Token:    entity.Token,
-		ExpireAt: entity.ExpireAt,
+		ID:            id,
+		Owner:         owner,
+		Device:        entity.Device,
+		UserAgent:     entity.UserAgent,
+		Token:         entity.Token,
+		PreviousToken: entity.PreviousToken,
+		CreatedAt:     entity.CreatedAt,
+		LastUsedAt:    entity.LastUsedAt,
+		ExpireAt:      entity.ExpireAt,
 	}
 }
 
 func toSessionEntity(model *Session) *entities.Session {
 	return &entities.Session{
-		ID:       model.ID.Hex(),
-		Owner:    model.Owner.Hex(),
-		Device:   model.Device,
-		Token:    model.Token,
-		ExpireAt: model.ExpireAt,
+		ID:            model.ID.Hex(),
+		Owner:         model.Owner.Hex(),
+		Device:        model.Device,
+		UserAgent:     model.UserAgent,
+		Token:         model.Token,
+		PreviousToken: model.PreviousToken,
+		CreatedAt:     model.CreatedAt,
+		LastUsedAt:    model.LastUsedAt,
+		ExpireAt:      model.ExpireAt,
+	}
+}
+
+// Tasks stored before statuses existed get the default status matching their
+// completion.
+func taskStatus(model *Task) string {
+	switch {
+	case model.Status != "":
+		return model.Status
+	case model.Completed:
+		return entities.StatusDone
+	default:
+		return entities.StatusTodo
 	}
 }
 
@@ -80,21 +250,230 @@ func toTaskModel(entity *entities.Task) *Task {
 	owner, _ := primitive.ObjectIDFromHex(entity.Owner)
 	return &Task{
 		ID:        id,
+		Version:   entity.Version,
 		Owner:     owner,
+		Project:   toReference(entity.Project),
+		Parent:    toReference(entity.Parent),
 		Name:      entity.Name,
+		Notes:     entity.Notes,
+		Status:    entity.Status,
 		Completed: entity.Completed,
-		CreatedAt: entity.CreatedAt,
-		UpdatedAt: entity.UpdatedAt,
+		Archived:  entity.Archived,
+		Priority:  int(entity.Priority),
+		Tags:      entity.Tags,
+		DueDate:   entity.DueDate,
+		DueTime:   entity.DueTime,
+		TimeZone:  entity.TimeZone,
+		DueAt:     entity.DueAt,
+		Position:  entity.Position,
+
+		Recurrence:          entity.Recurrence,
+		RecurFromCompletion: entity.RecurFromCompletion,
+
+		BlockedBy: objectIDs(entity.BlockedBy),
+		Blocked:   entity.Blocked,
+
+		CompletedAt: entity.CompletedAt,
+		CreatedAt:   entity.CreatedAt,
+		UpdatedAt:   entity.UpdatedAt,
+		DeletedAt:   entity.DeletedAt,
 	}
 }
 
 func toTaskEntity(entity *Task) *entities.Task {
 	return &entities.Task{
 		ID:        entity.ID.Hex(),
+		Version:   entity.Version,
 		Owner:     entity.Owner.Hex(),
+		Project:   fromReference(entity.Project),
+		Parent:    fromReference(entity.Parent),
 		Name:      entity.Name,
+		Notes:     entity.Notes,
+		Status:    taskStatus(entity),
 		Completed: entity.Completed,
+		Archived:  entity.Archived,
+		Priority:  entities.Priority(entity.Priority),
+		Tags:      entity.Tags,
+		DueDate:   entity.DueDate,
+		DueTime:   entity.DueTime,
+		TimeZone:  entity.TimeZone,
+		DueAt:     entity.DueAt,
+		Position:  entity.Position,
+
+		Recurrence:          entity.Recurrence,
+		RecurFromCompletion: entity.RecurFromCompletion,
+
+		BlockedBy: fromReferences(entity.BlockedBy),
+		Blocked:   entity.Blocked,
+
+		CompletedAt: entity.CompletedAt,
+		CreatedAt:   entity.CreatedAt,
+		UpdatedAt:   entity.UpdatedAt,
+		DeletedAt:   entity.DeletedAt,
+	}
+}
+
+func toProjectModel(entity *entities.Project) *Project {
+	id, _ := primitive.ObjectIDFromHex(entity.ID)
+	owner, _ := primitive.ObjectIDFromHex(entity.Owner)
+	return &Project{
+		ID:        id,
+		Owner:     owner,
+		Name:      entity.Name,
+		Color:     entity.Color,
+		Position:  entity.Position,
+		Archived:  entity.Archived,
+		CreatedAt: entity.CreatedAt,
+		UpdatedAt: entity.UpdatedAt,
+	}
+}
+
+func toProjectEntity(model *Project) *entities.Project {
+	return &entities.Project{
+		ID:        model.ID.Hex(),
+		Owner:     model.Owner.Hex(),
+		Name:      model.Name,
+		Color:     model.Color,
+		Position:  model.Position,
+		Archived:  model.Archived,
+		CreatedAt: model.CreatedAt,
+		UpdatedAt: model.UpdatedAt,
+	}
+}
+
+func toTimeEntryModel(entity *entities.TimeEntry) *TimeEntry {
+	id, _ := primitive.ObjectIDFromHex(entity.ID)
+	owner, _ := primitive.ObjectIDFromHex(entity.Owner)
+	task, _ := primitive.ObjectIDFromHex(entity.Task)
+	return &TimeEntry{
+		ID:        id,
+		Owner:     owner,
+		Task:      task,
+		Start:     entity.Start,
+		End:       entity.End,
+		Running:   entity.End == nil,
+		Note:      entity.Note,
+		CreatedAt: entity.CreatedAt,
+		UpdatedAt: entity.UpdatedAt,
+	}
+}
+
+func toTimeEntryEntity(model *TimeEntry) *entities.TimeEntry {
+	return &entities.TimeEntry{
+		ID:        model.ID.Hex(),
+		Owner:     model.Owner.Hex(),
+		Task:      model.Task.Hex(),
+		Start:     model.Start,
+		End:       model.End,
+		Note:      model.Note,
+		CreatedAt: model.CreatedAt,
+		UpdatedAt: model.UpdatedAt,
+	}
+}
+
+func toTemplateTaskModels(tasks []*entities.TemplateTask) []TemplateTask {
+	var models []TemplateTask
+	for _, task := range tasks {
+		models = append(models, TemplateTask{
+			Name:      task.Name,
+			Notes:     task.Notes,
+			Priority:  int(task.Priority),
+			Tags:      task.Tags,
+			DueOffset: task.DueOffset,
+			DueTime:   task.DueTime,
+			Subtasks:  toTemplateTaskModels(task.Subtasks),
+		})
+	}
+	return models
+}
+
+func toTemplateTaskEntities(models []TemplateTask) []*entities.TemplateTask {
+	var tasks []*entities.TemplateTask
+	for _, task := range models {
+		tasks = append(tasks, &entities.TemplateTask{
+			Name:      task.Name,
+			Notes:     task.Notes,
+			Priority:  entities.Priority(task.Priority),
+			Tags:      task.Tags,
+			DueOffset: task.DueOffset,
+			DueTime:   task.DueTime,
+			Subtasks:  toTemplateTaskEntities(task.Subtasks),
+		})
+	}
+	return tasks
+}
+
+func toTemplateModel(entity *entities.Template) *Template {
+	id, _ := primitive.ObjectIDFromHex(entity.ID)
+	owner, _ := primitive.ObjectIDFromHex(entity.Owner)
+	return &Template{
+		ID:        id,
+		Owner:     owner,
+		Name:      entity.Name,
+		Tasks:     toTemplateTaskModels(entity.Tasks),
+		CreatedAt: entity.CreatedAt,
+		UpdatedAt: entity.UpdatedAt,
+	}
+}
+
+func toTemplateEntity(model *Template) *entities.Template {
+	return &entities.Template{
+		ID:        model.ID.Hex(),
+		Owner:     model.Owner.Hex(),
+		Name:      model.Name,
+		Tasks:     toTemplateTaskEntities(model.Tasks),
+		CreatedAt: model.CreatedAt,
+		UpdatedAt: model.UpdatedAt,
+	}
+}
+
+func toFilterModel(entity *entities.Filter) *Filter {
+	id, _ := primitive.ObjectIDFromHex(entity.ID)
+	owner, _ := primitive.ObjectIDFromHex(entity.Owner)
+	return &Filter{
+		ID:        id,
+		Owner:     owner,
+		Name:      entity.Name,
+		Query:     entity.Query,
+		TimeZone:  entity.TimeZone,
 		CreatedAt: entity.CreatedAt,
 		UpdatedAt: entity.UpdatedAt,
 	}
 }
+
+func toFilterEntity(model *Filter) *entities.Filter {
+	return &entities.Filter{
+		ID:        model.ID.Hex(),
+		Owner:     model.Owner.Hex(),
+		Name:      model.Name,
+		Query:     model.Query,
+		TimeZone:  model.TimeZone,
+		CreatedAt: model.CreatedAt,
+		UpdatedAt: model.UpdatedAt,
+	}
+}
+
+func toCommentModel(entity *entities.Comment) *Comment {
+	id, _ := primitive.ObjectIDFromHex(entity.ID)
+	task, _ := primitive.ObjectIDFromHex(entity.Task)
+	author, _ := primitive.ObjectIDFromHex(entity.Author)
+	return &Comment{
+		ID:        id,
+		Task:      task,
+		Author:    author,
+		Body:      entity.Body,
+		CreatedAt: entity.CreatedAt,
+		EditedAt:  entity.EditedAt,
+	}
+}
+
+func toCommentEntity(model *Comment) *entities.Comment {
+	return &entities.Comment{
+		ID:        model.ID.Hex(),
+		Task:      model.Task.Hex(),
+		Author:    model.Author.Hex(),
+		Body:      model.Body,
+		CreatedAt: model.CreatedAt,
+		EditedAt:  model.EditedAt,
+	}
+}
@@ -0,0 +1,92 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/turbekoff/todo/internal/domain/entities"
+	"github.com/turbekoff/todo/internal/domain/repositories"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ProjectRepository struct {
+	db *mongo.Collection
+}
+
+func NewProjectRepository(db *mongo.Database) repositories.ProjectRepository {
+	return &ProjectRepository{db: db.Collection("projects")}
+}
+
+func (r *ProjectRepository) Create(project *entities.Project) error {
+	model := toProjectModel(project)
+	if model.ID.IsZero() {
+		model.ID = primitive.NewObjectID()
+	}
+
+	if _, err := r.db.InsertOne(context.Background(), model); err != nil {
+		return err
+	}
+
+	project.ID = model.ID.Hex()
+	return nil
+}
+
+func (r *ProjectRepository) Read(id string) (*entities.Project, error) {
+	objectID, _ := primitive.ObjectIDFromHex(id)
+
+	var project Project
+	if err := r.db.FindOne(context.Background(), bson.M{"_id": objectID}).Decode(&project); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, repositories.ErrProjectNotFound
+		}
+		return nil, err
+	}
+	return toProjectEntity(&project), nil
+}
+
+func (r *ProjectRepository) ReadAllByOwner(owner string) ([]*entities.Project, error) {
+	objectID, _ := primitive.ObjectIDFromHex(owner)
+
+	opts := options.Find().SetSort(bson.D{{Key: "position", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.db.Find(context.Background(), bson.M{"owner": objectID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []Project
+	if err = cursor.All(context.TODO(), &projects); err != nil {
+		return nil, err
+	}
+
+	var entities []*entities.Project
+	for _, project := range projects {
+		entities = append(entities, toProjectEntity(&project))
+	}
+
+	return entities, nil
+}
+
+func (r *ProjectRepository) Update(project *entities.Project) error {
+	model := toProjectModel(project)
+	query := bson.M{}
+	query["owner"] = model.Owner
+	query["name"] = model.Name
+	query["color"] = model.Color
+	query["position"] = model.Position
+	query["archived"] = model.Archived
+	query["createdAt"] = model.CreatedAt
+	query["updatedAt"] = model.UpdatedAt
+
+	_, err := r.db.UpdateOne(context.Background(), bson.M{"_id": model.ID}, bson.M{"$set": query})
+	return err
+}
+
+func (r *ProjectRepository) Delete(id string) error {
+	objectID, _ := primitive.ObjectIDFromHex(id)
+
+	_, err := r.db.DeleteOne(context.Background(), bson.M{"_id": objectID})
+	return err
+}
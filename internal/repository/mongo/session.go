@@ -3,6 +3,7 @@ package mongo
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/turbekoff/todo/internal/domain/entities"
 	"github.com/turbekoff/todo/internal/domain/repositories"
@@ -21,8 +22,16 @@ func NewSessionRepository(db *mongo.Database) repositories.SessionRepository {
 
 func (r *SessionRepository) Create(session *entities.Session) error {
 	model := toSessionModel(session)
-	_, err := r.db.InsertOne(context.Background(), model)
-	return err
+	if model.ID.IsZero() {
+		model.ID = primitive.NewObjectID()
+	}
+
+	if _, err := r.db.InsertOne(context.Background(), model); err != nil {
+		return err
+	}
+
+	session.ID = model.ID.Hex()
+	return nil
 }
 
 func (r *SessionRepository) Read(id string) (*entities.Session, error) {
@@ -49,6 +58,17 @@ func (r *SessionRepository) ReadByToken(token string) (*entities.Session, error)
 	return toSessionEntity(&session), nil
 }
 
+func (r *SessionRepository) ReadByPreviousToken(token string) (*entities.Session, error) {
+	var session Session
+	if err := r.db.FindOne(context.Background(), bson.M{"previousToken": token}).Decode(&session); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, repositories.ErrSessionNotFound
+		}
+		return nil, err
+	}
+	return toSessionEntity(&session), nil
+}
+
 func (r *SessionRepository) ReadAllByOwner(owner string) ([]*entities.Session, error) {
 	objectID, _ := primitive.ObjectIDFromHex(owner)
 
@@ -70,15 +90,44 @@ func (r *SessionRepository) ReadAllByOwner(owner string) ([]*entities.Session, e
 	return entities, nil
 }
 
-func (r *SessionRepository) Update(session *entities.Session) error {
-	model := toSessionModel(session)
+func sessionUpdate(model *Session) bson.M {
 	query := bson.M{}
 	query["owner"] = model.Owner
 	query["device"] = model.Device
+	query["userAgent"] = model.UserAgent
 	query["token"] = model.Token
+	query["previousToken"] = model.PreviousToken
+	query["createdAt"] = model.CreatedAt
+	query["lastUsedAt"] = model.LastUsedAt
 	query["expireAt"] = model.ExpireAt
+	return bson.M{"$set": query}
+}
+
+func (r *SessionRepository) Update(session *entities.Session) error {
+	model := toSessionModel(session)
+
+	_, err := r.db.UpdateOne(context.Background(), bson.M{"_id": model.ID}, sessionUpdate(model))
+	return err
+}
+
+func (r *SessionRepository) Rotate(session *entities.Session) error {
+	model := toSessionModel(session)
+
+	result, err := r.db.UpdateOne(context.Background(), bson.M{"_id": model.ID, "token": model.PreviousToken}, sessionUpdate(model))
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return repositories.ErrSessionConflict
+	}
+	return nil
+}
+
+func (r *SessionRepository) Touch(id string, at time.Time) error {
+	objectID, _ := primitive.ObjectIDFromHex(id)
 
-	_, err := r.db.UpdateOne(context.Background(), bson.M{"_id": model.ID}, bson.M{"$set": query})
+	_, err := r.db.UpdateOne(context.Background(), bson.M{"_id": objectID}, bson.M{"$set": bson.M{"lastUsedAt": at}})
 	return err
 }
 
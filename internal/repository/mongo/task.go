@@ -2,13 +2,17 @@ package mongo
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
+	"regexp"
+	"time"
 
 	"github.com/turbekoff/todo/internal/domain/entities"
 	"github.com/turbekoff/todo/internal/domain/repositories"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type TaskRepository struct {
@@ -21,8 +25,18 @@ func NewTaskRepository(db *mongo.Database) repositories.TaskRepository {
 
 func (r *TaskRepository) Create(task *entities.Task) error {
 	model := toTaskModel(task)
-	_, err := r.db.InsertOne(context.Background(), model)
-	return err
+	if model.ID.IsZero() {
+		model.ID = primitive.NewObjectID()
+	}
+	model.Version = 1
+
+	if _, err := r.db.InsertOne(context.Background(), model); err != nil {
+		return err
+	}
+
+	task.ID = model.ID.Hex()
+	task.Version = model.Version
+	return nil
 }
 
 func (r *TaskRepository) Read(id string) (*entities.Task, error) {
@@ -38,10 +52,185 @@ func (r *TaskRepository) Read(id string) (*entities.Task, error) {
 	return toTaskEntity(&task), nil
 }
 
+func taskFilter(filter *repositories.TaskFilter) bson.M {
+	owner, _ := primitive.ObjectIDFromHex(filter.Owner)
+	query := bson.M{"owner": owner, "deletedAt": nil}
+
+	if filter.Project != nil {
+		query["project"] = toReference(*filter.Project)
+	}
+
+	if filter.Parent != nil {
+		query["parent"] = toReference(*filter.Parent)
+	}
+
+	if filter.Completed != nil {
+		query["completed"] = *filter.Completed
+	}
+
+	if filter.Archived {
+		query["archived"] = true
+	} else {
+		query["archived"] = bson.M{"$ne": true}
+	}
+
+	if filter.Blocked != nil {
+		if *filter.Blocked {
+			query["blocked"] = true
+		} else {
+			query["blocked"] = bson.M{"$ne": true}
+		}
+	}
+
+	if filter.DueBefore != nil {
+		query["dueAt"] = bson.M{"$lt": *filter.DueBefore}
+	}
+
+	if filter.DueFrom != "" || filter.DueTo != "" {
+		due := bson.M{}
+		if filter.DueFrom != "" {
+			due["$gte"] = filter.DueFrom
+		}
+		if filter.DueTo != "" {
+			due["$lte"] = filter.DueTo
+		}
+		query["dueDate"] = due
+	}
+
+	if filter.WithoutDue {
+		query["dueDate"] = bson.M{"$in": bson.A{nil, ""}}
+	}
+
+	if len(filter.Tags) > 0 {
+		if filter.AllTags {
+			query["tags"] = bson.M{"$all": filter.Tags}
+		} else {
+			query["tags"] = bson.M{"$in": filter.Tags}
+		}
+	}
+
+	if filter.Condition != nil {
+		query["$and"] = bson.A{taskCondition(filter.Condition)}
+	}
+
+	return query
+}
+
+var conditionOperators = map[string]string{
+	repositories.ConditionLess:           "$lt",
+	repositories.ConditionLessOrEqual:    "$lte",
+	repositories.ConditionGreater:        "$gt",
+	repositories.ConditionGreaterOrEqual: "$gte",
+}
+
+// Flags are stored only when set, so a false flag matches the missing ones as
+// well.
+func taskCondition(condition *repositories.TaskCondition) bson.M {
+	var conditions bson.A
+	for _, c := range condition.Conditions {
+		conditions = append(conditions, taskCondition(c))
+	}
+
+	value := condition.Value
+	if project, ok := value.(string); ok && condition.Field == repositories.TaskFieldProject {
+		value = toReference(project)
+	}
+
+	switch condition.Op {
+	case repositories.ConditionAnd:
+		return bson.M{"$and": conditions}
+	case repositories.ConditionOr:
+		return bson.M{"$or": conditions}
+	case repositories.ConditionNot:
+		return bson.M{"$nor": conditions}
+	case repositories.ConditionEmpty:
+		return bson.M{condition.Field: bson.M{"$in": bson.A{nil, ""}}}
+	case repositories.ConditionContains:
+		text, _ := value.(string)
+		return bson.M{condition.Field: primitive.Regex{Pattern: regexp.QuoteMeta(text), Options: "i"}}
+	case repositories.ConditionEqual:
+		// Tasks stored before statuses are in the default status matching
+		// their completion, see taskStatus.
+		if condition.Field == repositories.TaskFieldStatus && (value == entities.StatusTodo || value == entities.StatusDone) {
+			return bson.M{"$or": bson.A{
+				bson.M{"status": value},
+				bson.M{"status": nil, "completed": value == entities.StatusDone},
+			}}
+		}
+
+		if value == false {
+			return bson.M{condition.Field: bson.M{"$ne": true}}
+		}
+		return bson.M{condition.Field: value}
+	default:
+		return bson.M{condition.Field: bson.M{conditionOperators[condition.Op]: value}}
+	}
+}
+
+// Ties are ordered by id, so tasks with equal values keep a stable order.
+func taskSort(sort *repositories.TaskSort) bson.D {
+	direction := 1
+	if sort.Descending {
+		direction = -1
+	}
+
+	return bson.D{{Key: sort.Field, Value: direction}, {Key: "_id", Value: direction}}
+}
+
+func (r *TaskRepository) ReadAll(filter *repositories.TaskFilter, sort *repositories.TaskSort) ([]*entities.Task, error) {
+	return r.find(taskFilter(filter), options.Find().SetSort(taskSort(sort)))
+}
+
 func (r *TaskRepository) ReadAllByOwner(owner string) ([]*entities.Task, error) {
 	objectID, _ := primitive.ObjectIDFromHex(owner)
+	return r.find(bson.M{"owner": objectID})
+}
+
+func objectIDs(ids []string) []primitive.ObjectID {
+	var result []primitive.ObjectID
+	for _, id := range ids {
+		if objectID, err := primitive.ObjectIDFromHex(id); err == nil {
+			result = append(result, objectID)
+		}
+	}
+	return result
+}
+
+func hexIDs(ids []primitive.ObjectID) []string {
+	var result []string
+	for _, id := range ids {
+		result = append(result, id.Hex())
+	}
+	return result
+}
+
+func (r *TaskRepository) ReadMany(owner string, ids []string) ([]*entities.Task, error) {
+	objectID, _ := primitive.ObjectIDFromHex(owner)
+	return r.find(bson.M{"_id": bson.M{"$in": objectIDs(ids)}, "owner": objectID, "deletedAt": nil})
+}
+
+func (r *TaskRepository) ReadDependents(ids []string) ([]*entities.Task, error) {
+	return r.find(bson.M{"blockedBy": bson.M{"$in": objectIDs(ids)}})
+}
+
+func (r *TaskRepository) SetBlocked(id string, blocked bool) error {
+	objectID, _ := primitive.ObjectIDFromHex(id)
 
-	cursor, err := r.db.Find(context.Background(), bson.M{"owner": objectID})
+	_, err := r.db.UpdateOne(
+		context.Background(),
+		bson.M{"_id": objectID},
+		bson.M{"$set": bson.M{"blocked": blocked}, "$inc": bson.M{"version": 1}},
+	)
+	return err
+}
+
+func (r *TaskRepository) ReadAllByParent(parent string) ([]*entities.Task, error) {
+	objectID, _ := primitive.ObjectIDFromHex(parent)
+	return r.find(bson.M{"parent": objectID, "deletedAt": nil}, options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}}))
+}
+
+func (r *TaskRepository) find(query bson.M, opts ...*options.FindOptions) ([]*entities.Task, error) {
+	cursor, err := r.db.Find(context.Background(), query, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -59,22 +248,431 @@ func (r *TaskRepository) ReadAllByOwner(owner string) ([]*entities.Task, error)
 	return entities, nil
 }
 
+func (r *TaskRepository) Search(owner, text string, archived bool, limit int) ([]*entities.Task, error) {
+	objectID, _ := primitive.ObjectIDFromHex(owner)
+	score := bson.M{"$meta": "textScore"}
+
+	query := bson.M{"owner": objectID, "deletedAt": nil, "$text": bson.M{"$search": text}}
+	if archived {
+		query["archived"] = true
+	} else {
+		query["archived"] = bson.M{"$ne": true}
+	}
+
+	return r.find(
+		query,
+		options.Find().
+			SetProjection(bson.M{"score": score}).
+			SetSort(bson.D{{Key: "score", Value: score}, {Key: "_id", Value: 1}}).
+			SetLimit(int64(limit)),
+	)
+}
+
+func (r *TaskRepository) ReadTags(owner string) ([]*entities.TagUsage, error) {
+	objectID, _ := primitive.ObjectIDFromHex(owner)
+
+	cursor, err := r.db.Aggregate(context.Background(), mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"owner": objectID, "deletedAt": nil}}},
+		{{Key: "$unwind", Value: "$tags"}},
+		{{Key: "$group", Value: bson.M{"_id": "$tags", "count": bson.M{"$sum": 1}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []struct {
+		Name  string `bson:"_id"`
+		Count int    `bson:"count"`
+	}
+	if err = cursor.All(context.TODO(), &tags); err != nil {
+		return nil, err
+	}
+
+	var usages []*entities.TagUsage
+	for _, tag := range tags {
+		usages = append(usages, &entities.TagUsage{Name: tag.Name, Count: tag.Count})
+	}
+
+	return usages, nil
+}
+
+func (r *TaskRepository) ReadAdjacent(owner, position string, before bool, exclude string) (*entities.Task, error) {
+	objectID, _ := primitive.ObjectIDFromHex(owner)
+	excluded, _ := primitive.ObjectIDFromHex(exclude)
+
+	compare, direction := "$gt", 1
+	if before {
+		compare, direction = "$lt", -1
+	}
+
+	// Tasks without a position, including ones stored with an empty one, have
+	// no rank to compare with.
+	ranked := bson.M{"$gt": ""}
+	if position != "" {
+		ranked[compare] = position
+	}
+	query := bson.M{"owner": objectID, "_id": bson.M{"$ne": excluded}, "position": ranked, "deletedAt": nil}
+
+	tasks, err := r.find(query, options.Find().
+		SetSort(bson.D{{Key: "position", Value: direction}, {Key: "_id", Value: direction}}).
+		SetLimit(1),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tasks) == 0 {
+		return nil, repositories.ErrTaskNotFound
+	}
+	return tasks[0], nil
+}
+
 func (r *TaskRepository) Update(task *entities.Task) error {
 	model := toTaskModel(task)
 	query := bson.M{}
 	query["owner"] = model.Owner
+	query["project"] = model.Project
+	query["parent"] = model.Parent
 	query["name"] = model.Name
+	query["notes"] = model.Notes
+	query["status"] = model.Status
 	query["completed"] = model.Completed
+	query["archived"] = model.Archived
+	query["completedAt"] = model.CompletedAt
+	query["priority"] = model.Priority
+	query["tags"] = model.Tags
+	query["dueDate"] = model.DueDate
+	query["dueTime"] = model.DueTime
+	query["timeZone"] = model.TimeZone
+	query["dueAt"] = model.DueAt
+	query["recurrence"] = model.Recurrence
+	query["recurFromCompletion"] = model.RecurFromCompletion
+	query["blockedBy"] = model.BlockedBy
+	query["blocked"] = model.Blocked
 	query["createdAt"] = model.CreatedAt
 	query["updatedAt"] = model.UpdatedAt
+	query["deletedAt"] = model.DeletedAt
+
+	// The update only applies to the version of the task it was based on,
+	// tasks stored before versioning have none.
+	version := bson.M{"version": model.Version}
+	if model.Version == 0 {
+		version = bson.M{"version": bson.M{"$in": bson.A{0, nil}}}
+	}
+
+	update := bson.M{"$set": query, "$inc": bson.M{"version": 1}}
+	if model.Position != "" {
+		query["position"] = model.Position
+	} else {
+		update["$unset"] = bson.M{"position": ""}
+	}
+
+	result, err := r.db.UpdateOne(context.Background(), bson.M{"_id": model.ID, "$and": bson.A{version}}, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		if _, err := r.Read(task.ID); err != nil {
+			return err
+		}
+		return repositories.ErrTaskConflict
+	}
+
+	task.Version++
+	return nil
+}
+
+func (r *TaskRepository) UpdateMany(owner string, ids []string, change *repositories.TaskChange) error {
+	objectID, _ := primitive.ObjectIDFromHex(owner)
+	query := bson.M{"_id": bson.M{"$in": objectIDs(ids)}, "owner": objectID, "deletedAt": nil}
+	set := bson.M{"updatedAt": change.At}
+	update := bson.M{"$set": set, "$inc": bson.M{"version": 1}}
+
+	if change.Completed != nil {
+		query["completed"] = bson.M{"$ne": *change.Completed}
+		set["completed"] = *change.Completed
+		if change.Status != "" {
+			set["status"] = change.Status
+		}
+		if *change.Completed {
+			set["completedAt"] = change.At
+		} else {
+			set["archived"] = false
+			update["$unset"] = bson.M{"completedAt": ""}
+		}
+	}
+
+	if change.Project != nil {
+		set["project"] = toReference(*change.Project)
+	}
+
+	if change.AddTag != "" {
+		update["$addToSet"] = bson.M{"tags": change.AddTag}
+	}
+
+	_, err := r.db.UpdateMany(context.Background(), query, update)
+	return err
+}
+
+func (r *TaskRepository) MoveAllByProject(project, to string) error {
+	objectID, _ := primitive.ObjectIDFromHex(project)
+
+	_, err := r.db.UpdateMany(context.Background(), bson.M{"project": objectID}, bson.M{"$set": bson.M{"project": toReference(to)}, "$inc": bson.M{"version": 1}})
+	return err
+}
+
+func (r *TaskRepository) withSubtasks(query bson.M) ([]primitive.ObjectID, error) {
+	cursor, err := r.db.Aggregate(context.Background(), mongo.Pipeline{
+		{{Key: "$match", Value: query}},
+		{{Key: "$graphLookup", Value: bson.M{
+			"from":             r.db.Name(),
+			"startWith":        "$_id",
+			"connectFromField": "_id",
+			"connectToField":   "parent",
+			"as":               "subtasks",
+		}}},
+		{{Key: "$project", Value: bson.M{"subtasks._id": 1}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []struct {
+		ID       primitive.ObjectID `bson:"_id"`
+		Subtasks []struct {
+			ID primitive.ObjectID `bson:"_id"`
+		} `bson:"subtasks"`
+	}
+	if err = cursor.All(context.TODO(), &tasks); err != nil {
+		return nil, err
+	}
+
+	var ids []primitive.ObjectID
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+		for _, subtask := range task.Subtasks {
+			ids = append(ids, subtask.ID)
+		}
+	}
+
+	return ids, nil
+}
+
+func (r *TaskRepository) deleteWithSubtasks(query bson.M) ([]string, error) {
+	ids, err := r.withSubtasks(query)
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+
+	if _, err = r.db.DeleteMany(context.Background(), bson.M{"_id": bson.M{"$in": ids}}); err != nil {
+		return nil, err
+	}
+	return hexIDs(ids), nil
+}
+
+func (r *TaskRepository) Archive(owner string, completedBefore time.Time) (int64, error) {
+	objectID, _ := primitive.ObjectIDFromHex(owner)
+
+	result, err := r.db.UpdateMany(
+		context.Background(),
+		bson.M{
+			"owner":     objectID,
+			"completed": true,
+			"archived":  bson.M{"$ne": true},
+			"deletedAt": nil,
+			"$or": bson.A{
+				bson.M{"completedAt": bson.M{"$lt": completedBefore}},
+				bson.M{"completedAt": nil, "updatedAt": bson.M{"$lt": completedBefore}},
+			},
+		},
+		bson.M{"$set": bson.M{"archived": true}, "$inc": bson.M{"version": 1}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func (r *TaskRepository) ReadTrash(owner string) ([]*entities.Task, error) {
+	objectID, _ := primitive.ObjectIDFromHex(owner)
+	return r.find(
+		bson.M{"owner": objectID, "deletedAt": bson.M{"$ne": nil}},
+		options.Find().SetSort(bson.D{{Key: "deletedAt", Value: -1}, {Key: "_id", Value: -1}}),
+	)
+}
+
+func (r *TaskRepository) trash(query bson.M, at time.Time) ([]string, error) {
+	ids, err := r.withSubtasks(query)
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+
+	_, err = r.db.UpdateMany(
+		context.Background(),
+		bson.M{"_id": bson.M{"$in": ids}, "deletedAt": nil},
+		bson.M{"$set": bson.M{"deletedAt": at}, "$inc": bson.M{"version": 1}},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return hexIDs(ids), nil
+}
+
+func (r *TaskRepository) Trash(id string, at time.Time) error {
+	objectID, _ := primitive.ObjectIDFromHex(id)
+	_, err := r.trash(bson.M{"_id": objectID, "deletedAt": nil}, at)
+	return err
+}
 
-	_, err := r.db.UpdateOne(context.Background(), bson.M{"_id": model.ID}, bson.M{"$set": query})
+func (r *TaskRepository) TrashMany(owner string, ids []string, at time.Time) error {
+	objectID, _ := primitive.ObjectIDFromHex(owner)
+	_, err := r.trash(bson.M{"_id": bson.M{"$in": objectIDs(ids)}, "owner": objectID, "deletedAt": nil}, at)
 	return err
 }
 
-func (r *TaskRepository) Delete(id string) error {
+func (r *TaskRepository) TrashAllByProject(project string, at time.Time) ([]string, error) {
+	objectID, _ := primitive.ObjectIDFromHex(project)
+	return r.trash(bson.M{"project": objectID, "deletedAt": nil}, at)
+}
+
+func (r *TaskRepository) Restore(id string) error {
+	task, err := r.Read(id)
+	if err != nil || task.DeletedAt == nil {
+		return err
+	}
+
 	objectID, _ := primitive.ObjectIDFromHex(id)
+	ids, err := r.withSubtasks(bson.M{"_id": objectID})
+	if err != nil {
+		return err
+	}
 
-	_, err := r.db.DeleteOne(context.Background(), bson.M{"_id": objectID})
+	_, err = r.db.UpdateMany(
+		context.Background(),
+		bson.M{"_id": bson.M{"$in": ids}, "deletedAt": *task.DeletedAt},
+		bson.M{"$unset": bson.M{"deletedAt": ""}, "$inc": bson.M{"version": 1}},
+	)
 	return err
 }
+
+func (r *TaskRepository) Purge(before time.Time) ([]string, error) {
+	return r.deleteWithSubtasks(bson.M{"deletedAt": bson.M{"$lt": before}})
+}
+
+func (r *TaskRepository) Delete(id string) ([]string, error) {
+	objectID, _ := primitive.ObjectIDFromHex(id)
+	return r.deleteWithSubtasks(bson.M{"_id": objectID})
+}
+
+// A cursor holds the sort value and id of the last task of a page, so tasks
+// inserted in between don't shift the following pages.
+type taskCursor struct {
+	Field string             `bson:"f"`
+	Value interface{}        `bson:"v"`
+	ID    primitive.ObjectID `bson:"i"`
+}
+
+// The sort value is taken from the raw document, since a missing field sorts
+// differently from its zero value.
+func encodeTaskCursor(field string, document bson.Raw) (string, error) {
+	cursor := &taskCursor{Field: field}
+	if value, err := document.LookupErr(field); err == nil {
+		cursor.Value = value
+	}
+	if err := document.Lookup("_id").Unmarshal(&cursor.ID); err != nil {
+		return "", err
+	}
+
+	data, err := bson.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeTaskCursor(field, s string) (*taskCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, repositories.ErrInvalidCursor
+	}
+
+	var cursor taskCursor
+	if err := bson.Unmarshal(data, &cursor); err != nil || cursor.Field != field {
+		return nil, repositories.ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// Missing values come first in ascending order and last in descending order.
+func (c *taskCursor) after(descending bool) bson.M {
+	next, nulls := "$gt", bson.M{c.Field: bson.M{"$ne": nil}}
+	if descending {
+		next, nulls = "$lt", bson.M{c.Field: nil}
+	}
+
+	tie := bson.M{c.Field: c.Value, "_id": bson.M{next: c.ID}}
+	if c.Value == nil {
+		if descending {
+			return tie
+		}
+		return bson.M{"$or": bson.A{nulls, tie}}
+	}
+
+	following := bson.A{bson.M{c.Field: bson.M{next: c.Value}}, tie}
+	if descending {
+		following = append(following, nulls)
+	}
+	return bson.M{"$or": following}
+}
+
+func (r *TaskRepository) ReadPage(filter *repositories.TaskFilter, sort *repositories.TaskSort, page *repositories.Page) (*repositories.TaskPage, error) {
+	query := taskFilter(filter)
+	result := &repositories.TaskPage{}
+
+	if page.WithTotal {
+		total, err := r.db.CountDocuments(context.Background(), query)
+		if err != nil {
+			return nil, err
+		}
+		result.Total = &total
+	}
+
+	if page.Cursor != "" {
+		cursor, err := decodeTaskCursor(sort.Field, page.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		and, _ := query["$and"].(bson.A)
+		query["$and"] = append(and, cursor.after(sort.Descending))
+	}
+
+	opts := options.Find().SetSort(taskSort(sort)).SetLimit(int64(page.Limit) + 1)
+	cursor, err := r.db.Find(context.Background(), query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var documents []bson.Raw
+	if err = cursor.All(context.TODO(), &documents); err != nil {
+		return nil, err
+	}
+
+	if len(documents) > page.Limit {
+		documents = documents[:page.Limit]
+		if result.NextCursor, err = encodeTaskCursor(sort.Field, documents[len(documents)-1]); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, document := range documents {
+		var task Task
+		if err := bson.Unmarshal(document, &task); err != nil {
+			return nil, err
+		}
+		result.Tasks = append(result.Tasks, toTaskEntity(&task))
+	}
+
+	return result, nil
+}
@@ -0,0 +1,95 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/turbekoff/todo/internal/domain/entities"
+	"github.com/turbekoff/todo/internal/domain/repositories"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type TemplateRepository struct {
+	db *mongo.Collection
+}
+
+func NewTemplateRepository(db *mongo.Database) repositories.TemplateRepository {
+	return &TemplateRepository{db: db.Collection("templates")}
+}
+
+func (r *TemplateRepository) Create(template *entities.Template) error {
+	model := toTemplateModel(template)
+	if model.ID.IsZero() {
+		model.ID = primitive.NewObjectID()
+	}
+
+	if _, err := r.db.InsertOne(context.Background(), model); err != nil {
+		return err
+	}
+
+	template.ID = model.ID.Hex()
+	return nil
+}
+
+func (r *TemplateRepository) Read(id string) (*entities.Template, error) {
+	objectID, _ := primitive.ObjectIDFromHex(id)
+
+	var template Template
+	if err := r.db.FindOne(context.Background(), bson.M{"_id": objectID}).Decode(&template); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, repositories.ErrTemplateNotFound
+		}
+		return nil, err
+	}
+	return toTemplateEntity(&template), nil
+}
+
+func (r *TemplateRepository) ReadAllByOwner(owner string) ([]*entities.Template, error) {
+	objectID, _ := primitive.ObjectIDFromHex(owner)
+
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.db.Find(context.Background(), bson.M{"owner": objectID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var templates []Template
+	if err = cursor.All(context.TODO(), &templates); err != nil {
+		return nil, err
+	}
+
+	var entities []*entities.Template
+	for _, template := range templates {
+		entities = append(entities, toTemplateEntity(&template))
+	}
+
+	return entities, nil
+}
+
+func (r *TemplateRepository) Update(template *entities.Template) error {
+	model := toTemplateModel(template)
+	query := bson.M{}
+	query["name"] = model.Name
+	query["tasks"] = model.Tasks
+	query["updatedAt"] = model.UpdatedAt
+
+	_, err := r.db.UpdateOne(context.Background(), bson.M{"_id": model.ID}, bson.M{"$set": query})
+	return err
+}
+
+func (r *TemplateRepository) Delete(id string) error {
+	objectID, _ := primitive.ObjectIDFromHex(id)
+
+	_, err := r.db.DeleteOne(context.Background(), bson.M{"_id": objectID})
+	return err
+}
+
+func (r *TemplateRepository) DeleteAllByOwner(owner string) error {
+	objectID, _ := primitive.ObjectIDFromHex(owner)
+
+	_, err := r.db.DeleteMany(context.Background(), bson.M{"owner": objectID})
+	return err
+}
@@ -0,0 +1,142 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/turbekoff/todo/internal/domain/entities"
+	"github.com/turbekoff/todo/internal/domain/repositories"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type TimeEntryRepository struct {
+	db *mongo.Collection
+}
+
+func NewTimeEntryRepository(db *mongo.Database) repositories.TimeEntryRepository {
+	return &TimeEntryRepository{db: db.Collection("timeEntries")}
+}
+
+func (r *TimeEntryRepository) Create(entry *entities.TimeEntry) error {
+	model := toTimeEntryModel(entry)
+	if model.ID.IsZero() {
+		model.ID = primitive.NewObjectID()
+	}
+
+	if _, err := r.db.InsertOne(context.Background(), model); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return repositories.ErrTimerRunning
+		}
+		return err
+	}
+
+	entry.ID = model.ID.Hex()
+	return nil
+}
+
+func (r *TimeEntryRepository) read(query bson.M) (*entities.TimeEntry, error) {
+	var entry TimeEntry
+	if err := r.db.FindOne(context.Background(), query).Decode(&entry); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, repositories.ErrTimeEntryNotFound
+		}
+		return nil, err
+	}
+	return toTimeEntryEntity(&entry), nil
+}
+
+func (r *TimeEntryRepository) Read(id string) (*entities.TimeEntry, error) {
+	objectID, _ := primitive.ObjectIDFromHex(id)
+	return r.read(bson.M{"_id": objectID})
+}
+
+func (r *TimeEntryRepository) ReadRunning(owner string) (*entities.TimeEntry, error) {
+	objectID, _ := primitive.ObjectIDFromHex(owner)
+	return r.read(bson.M{"owner": objectID, "running": true})
+}
+
+func (r *TimeEntryRepository) find(query bson.M, opts ...*options.FindOptions) ([]*entities.TimeEntry, error) {
+	cursor, err := r.db.Find(context.Background(), query, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []TimeEntry
+	if err = cursor.All(context.TODO(), &entries); err != nil {
+		return nil, err
+	}
+
+	var entities []*entities.TimeEntry
+	for _, entry := range entries {
+		entities = append(entities, toTimeEntryEntity(&entry))
+	}
+
+	return entities, nil
+}
+
+func (r *TimeEntryRepository) ReadAllByTask(task string) ([]*entities.TimeEntry, error) {
+	objectID, _ := primitive.ObjectIDFromHex(task)
+	return r.find(bson.M{"task": objectID}, options.Find().SetSort(bson.D{{Key: "start", Value: -1}, {Key: "_id", Value: -1}}))
+}
+
+func (r *TimeEntryRepository) ReadAllByPeriod(owner string, from, to time.Time) ([]*entities.TimeEntry, error) {
+	objectID, _ := primitive.ObjectIDFromHex(owner)
+
+	return r.find(bson.M{
+		"owner": objectID,
+		"start": bson.M{"$lt": to},
+		"$or":   bson.A{bson.M{"end": bson.M{"$gt": from}}, bson.M{"running": true}},
+	}, options.Find().SetSort(bson.D{{Key: "start", Value: 1}, {Key: "_id", Value: 1}}))
+}
+
+func (r *TimeEntryRepository) Update(entry *entities.TimeEntry) error {
+	model := toTimeEntryModel(entry)
+	query := bson.M{}
+	query["start"] = model.Start
+	query["end"] = model.End
+	query["note"] = model.Note
+	query["createdAt"] = model.CreatedAt
+	query["updatedAt"] = model.UpdatedAt
+
+	update := bson.M{"$set": query}
+	if model.Running {
+		query["running"] = true
+	} else {
+		update["$unset"] = bson.M{"running": ""}
+	}
+
+	if _, err := r.db.UpdateOne(context.Background(), bson.M{"_id": model.ID}, update); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return repositories.ErrTimerRunning
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *TimeEntryRepository) Delete(id string) error {
+	objectID, _ := primitive.ObjectIDFromHex(id)
+
+	_, err := r.db.DeleteOne(context.Background(), bson.M{"_id": objectID})
+	return err
+}
+
+func (r *TimeEntryRepository) DeleteAllByOwner(owner string) error {
+	objectID, _ := primitive.ObjectIDFromHex(owner)
+
+	_, err := r.db.DeleteMany(context.Background(), bson.M{"owner": objectID})
+	return err
+}
+
+func (r *TimeEntryRepository) DeleteAllByTask(ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	_, err := r.db.DeleteMany(context.Background(), bson.M{"task": bson.M{"$in": objectIDs(ids)}})
+	return err
+}
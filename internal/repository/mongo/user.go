@@ -49,11 +49,32 @@ func (r *UserRepository) ReadByName(name string) (*entities.User, error) {
 	return toUserEntity(&user), nil
 }
 
+func (r *UserRepository) ReadAllWithAutoArchive() ([]*entities.User, error) {
+	cursor, err := r.db.Find(context.Background(), bson.M{"autoArchiveDays": bson.M{"$gt": 0}})
+	if err != nil {
+		return nil, err
+	}
+
+	var users []User
+	if err = cursor.All(context.TODO(), &users); err != nil {
+		return nil, err
+	}
+
+	var entities []*entities.User
+	for _, user := range users {
+		entities = append(entities, toUserEntity(&user))
+	}
+
+	return entities, nil
+}
+
 func (r *UserRepository) Update(user *entities.User) error {
 	model := toUserModel(user)
 	query := bson.M{}
 	query["name"] = model.Name
 	query["password"] = model.Password
+	query["autoArchiveDays"] = model.AutoArchiveDays
+	query["workflow"] = model.Workflow
 	query["createdAt"] = model.CreatedAt
 	query["updatedAt"] = model.UpdatedAt
 
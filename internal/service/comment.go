@@ -0,0 +1,125 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/turbekoff/todo/internal/domain/entities"
+	"github.com/turbekoff/todo/internal/domain/repositories"
+)
+
+const MaxCommentLength = 5000
+
+var (
+	ErrCommentAuthorization = errors.New("only the owner of the task may comment on it")
+	ErrCommentLength        = fmt.Errorf("comment can't be longer than %d characters", MaxCommentLength)
+)
+
+type commentService struct {
+	taskRepository    repositories.TaskRepository
+	commentRepository repositories.CommentRepository
+}
+
+func NewCommentService(
+	taskRepository repositories.TaskRepository,
+	commentRepository repositories.CommentRepository,
+) CommentService {
+	return &commentService{
+		taskRepository:    taskRepository,
+		commentRepository: commentRepository,
+	}
+}
+
+func validateComment(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", errors.New("empty body specified")
+	}
+
+	if utf8.RuneCountInString(body) > MaxCommentLength {
+		return "", ErrCommentLength
+	}
+	return body, nil
+}
+
+func (s *commentService) task(id string) (*entities.Task, error) {
+	task, err := s.taskRepository.Read(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if task.DeletedAt != nil {
+		return nil, ErrTaskTrashed
+	}
+	return task, nil
+}
+
+func (s *commentService) Create(task, author, body string) (*entities.Comment, error) {
+	body, err := validateComment(body)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := s.task(task)
+	if err != nil {
+		return nil, err
+	}
+
+	// Tasks can't be shared, so the owner is the only participant of the
+	// discussion.
+	if t.Owner != author {
+		return nil, ErrCommentAuthorization
+	}
+
+	comment := &entities.Comment{
+		Task:      t.ID,
+		Author:    author,
+		Body:      body,
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.commentRepository.Create(comment); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+func (s *commentService) Read(id string) (*entities.Comment, error) {
+	return s.commentRepository.Read(id)
+}
+
+func (s *commentService) ReadAllByTask(task string) ([]*entities.Comment, error) {
+	return s.commentRepository.ReadAllByTask(task)
+}
+
+func (s *commentService) Update(id, body string) (*entities.Comment, error) {
+	body, err := validateComment(body)
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err := s.commentRepository.Read(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.task(comment.Task); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	comment.Body = body
+	comment.EditedAt = &now
+
+	if err := s.commentRepository.Update(comment); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+func (s *commentService) Delete(id string) error {
+	return s.commentRepository.Delete(id)
+}
@@ -0,0 +1,302 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/turbekoff/todo/internal/domain/entities"
+	"github.com/turbekoff/todo/internal/domain/repositories"
+	"github.com/turbekoff/todo/pkg/expr"
+)
+
+const (
+	MaxFilterNameLength  = 100
+	MaxFilterQueryLength = 500
+)
+
+// Longer prefixes first.
+var comparisons = []struct {
+	prefix string
+	op     string
+}{
+	{">=", repositories.ConditionGreaterOrEqual},
+	{"<=", repositories.ConditionLessOrEqual},
+	{">", repositories.ConditionGreater},
+	{"<", repositories.ConditionLess},
+}
+
+type filterService struct {
+	userRepository   repositories.UserRepository
+	taskRepository   repositories.TaskRepository
+	filterRepository repositories.FilterRepository
+}
+
+func NewFilterService(
+	userRepository repositories.UserRepository,
+	taskRepository repositories.TaskRepository,
+	filterRepository repositories.FilterRepository,
+) FilterService {
+	return &filterService{
+		userRepository:   userRepository,
+		taskRepository:   taskRepository,
+		filterRepository: filterRepository,
+	}
+}
+
+func comparison(value string) (string, string) {
+	for _, c := range comparisons {
+		if strings.HasPrefix(value, c.prefix) {
+			return c.op, strings.TrimPrefix(value, c.prefix)
+		}
+	}
+	return repositories.ConditionEqual, value
+}
+
+func taskField(field, op string, value interface{}) *repositories.TaskCondition {
+	return &repositories.TaskCondition{Op: op, Field: field, Value: value}
+}
+
+func dueBetween(from, to time.Time) *repositories.TaskCondition {
+	return &repositories.TaskCondition{Op: repositories.ConditionAnd, Conditions: []*repositories.TaskCondition{
+		taskField(repositories.TaskFieldDueDate, repositories.ConditionGreaterOrEqual, from.Format(dueDateLayout)),
+		taskField(repositories.TaskFieldDueDate, repositories.ConditionLessOrEqual, to.Format(dueDateLayout)),
+	}}
+}
+
+func compileTerm(node *expr.Node, now time.Time) (*repositories.TaskCondition, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	monday := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+
+	switch key := strings.ToLower(node.Key); key {
+	case "":
+	case "tag":
+		tags, err := normalizeTags([]string{node.Value})
+		if err != nil {
+			return nil, err
+		}
+		return taskField(repositories.TaskFieldTags, repositories.ConditionEqual, tags[0]), nil
+	case "project":
+		if strings.EqualFold(node.Value, ProjectInbox) {
+			return taskField(repositories.TaskFieldProject, repositories.ConditionEqual, ""), nil
+		}
+		if node.Value == "" {
+			return nil, errors.New("empty project specified")
+		}
+		return taskField(repositories.TaskFieldProject, repositories.ConditionEqual, node.Value), nil
+	case "status":
+		if node.Value == "" {
+			return nil, errors.New("empty status specified")
+		}
+		return taskField(repositories.TaskFieldStatus, repositories.ConditionEqual, node.Value), nil
+	case "priority":
+		op, value := comparison(node.Value)
+		if value == "" {
+			return nil, errors.New("empty priority specified")
+		}
+
+		priority, err := entities.ParsePriority(strings.ToLower(value))
+		if err != nil {
+			return nil, err
+		}
+		return taskField(repositories.TaskFieldPriority, op, int(priority)), nil
+	case "due":
+		op, value := comparison(node.Value)
+		if strings.EqualFold(value, DueNone) && op == repositories.ConditionEqual {
+			return taskField(repositories.TaskFieldDueDate, repositories.ConditionEmpty, nil), nil
+		}
+
+		if _, err := time.Parse(dueDateLayout, value); err != nil {
+			return nil, errors.New("due must be formatted as YYYY-MM-DD")
+		}
+
+		// Tasks without a due date may store an empty one, which sorts before
+		// every date.
+		if op == repositories.ConditionLess || op == repositories.ConditionLessOrEqual {
+			return &repositories.TaskCondition{Op: repositories.ConditionAnd, Conditions: []*repositories.TaskCondition{
+				taskField(repositories.TaskFieldDueDate, op, value),
+				{Op: repositories.ConditionNot, Conditions: []*repositories.TaskCondition{
+					taskField(repositories.TaskFieldDueDate, repositories.ConditionEmpty, nil),
+				}},
+			}}, nil
+		}
+		return taskField(repositories.TaskFieldDueDate, op, value), nil
+	case "name":
+		if node.Value == "" {
+			return nil, errors.New("empty name specified")
+		}
+		return taskField(repositories.TaskFieldName, repositories.ConditionContains, node.Value), nil
+	default:
+		return nil, fmt.Errorf("unknown filter key %q", key)
+	}
+
+	switch phrase := strings.ToLower(node.Value); phrase {
+	case "completed":
+		return taskField(repositories.TaskFieldCompleted, repositories.ConditionEqual, true), nil
+	case "blocked":
+		return taskField(repositories.TaskFieldBlocked, repositories.ConditionEqual, true), nil
+	case "overdue":
+		return &repositories.TaskCondition{Op: repositories.ConditionAnd, Conditions: []*repositories.TaskCondition{
+			taskField(repositories.TaskFieldCompleted, repositories.ConditionEqual, false),
+			taskField(repositories.TaskFieldDueAt, repositories.ConditionLess, now),
+		}}, nil
+	case "due today":
+		return taskField(repositories.TaskFieldDueDate, repositories.ConditionEqual, today.Format(dueDateLayout)), nil
+	case "due tomorrow":
+		return taskField(repositories.TaskFieldDueDate, repositories.ConditionEqual, today.AddDate(0, 0, 1).Format(dueDateLayout)), nil
+	case "due this week":
+		return dueBetween(monday, monday.AddDate(0, 0, 6)), nil
+	case "due next week":
+		return dueBetween(monday.AddDate(0, 0, 7), monday.AddDate(0, 0, 13)), nil
+	case "no due date":
+		return taskField(repositories.TaskFieldDueDate, repositories.ConditionEmpty, nil), nil
+	default:
+		return nil, fmt.Errorf("unknown filter term %q", node.Value)
+	}
+}
+
+func compileNode(node *expr.Node, now time.Time) (*repositories.TaskCondition, error) {
+	if node.Op == expr.Term {
+		return compileTerm(node, now)
+	}
+
+	condition := &repositories.TaskCondition{}
+	switch node.Op {
+	case expr.And:
+		condition.Op = repositories.ConditionAnd
+	case expr.Or:
+		condition.Op = repositories.ConditionOr
+	case expr.Not:
+		condition.Op = repositories.ConditionNot
+	}
+
+	for _, child := range node.Children {
+		c, err := compileNode(child, now)
+		if err != nil {
+			return nil, err
+		}
+		condition.Conditions = append(condition.Conditions, c)
+	}
+	return condition, nil
+}
+
+func compileQuery(query, timeZone string) (*repositories.TaskCondition, error) {
+	loc, err := location(timeZone)
+	if err != nil {
+		return nil, err
+	}
+
+	node, err := expr.Parse(query)
+	if err != nil {
+		return nil, err
+	}
+	return compileNode(node, time.Now().In(loc))
+}
+
+func (s *filterService) validate(input *FilterInput) error {
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" {
+		return errors.New("empty name specified")
+	}
+
+	if utf8.RuneCountInString(input.Name) > MaxFilterNameLength {
+		return errors.New("filter name is too long")
+	}
+
+	input.Query = strings.TrimSpace(input.Query)
+	if utf8.RuneCountInString(input.Query) > MaxFilterQueryLength {
+		return fmt.Errorf("query can't be longer than %d characters", MaxFilterQueryLength)
+	}
+
+	_, err := compileQuery(input.Query, input.TimeZone)
+	return err
+}
+
+func (s *filterService) Create(owner string, input *FilterInput) (*entities.Filter, error) {
+	if err := s.validate(input); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.userRepository.Read(owner); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	filter := &entities.Filter{
+		Owner:     owner,
+		Name:      input.Name,
+		Query:     input.Query,
+		TimeZone:  input.TimeZone,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := s.filterRepository.Create(filter); err != nil {
+		return nil, err
+	}
+
+	return filter, nil
+}
+
+func (s *filterService) Read(id string) (*entities.Filter, error) {
+	return s.filterRepository.Read(id)
+}
+
+func (s *filterService) ReadAllByOwner(owner string) ([]*entities.Filter, error) {
+	return s.filterRepository.ReadAllByOwner(owner)
+}
+
+func (s *filterService) Update(id string, input *FilterInput) (*entities.Filter, error) {
+	if err := s.validate(input); err != nil {
+		return nil, err
+	}
+
+	filter, err := s.filterRepository.Read(id)
+	if err != nil {
+		return nil, err
+	}
+
+	filter.Name = input.Name
+	filter.Query = input.Query
+	filter.TimeZone = input.TimeZone
+	filter.UpdatedAt = time.Now()
+
+	if err := s.filterRepository.Update(filter); err != nil {
+		return nil, err
+	}
+
+	return filter, nil
+}
+
+func (s *filterService) Delete(id string) error {
+	return s.filterRepository.Delete(id)
+}
+
+func (s *filterService) ReadTasks(id string, query *FilterTaskQuery) (*repositories.TaskPage, error) {
+	filter, err := s.filterRepository.Read(id)
+	if err != nil {
+		return nil, err
+	}
+
+	sort, err := taskSort(query.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &repositories.Page{Limit: query.Limit, Cursor: query.Cursor, WithTotal: query.WithTotal}
+	switch {
+	case page.Limit == 0:
+		page.Limit = DefaultTaskPageLimit
+	case page.Limit < 0 || page.Limit > MaxTaskPageLimit:
+		return nil, fmt.Errorf("limit must be between 1 and %d", MaxTaskPageLimit)
+	}
+
+	condition, err := compileQuery(filter.Query, filter.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.taskRepository.ReadPage(&repositories.TaskFilter{Owner: filter.Owner, Condition: condition}, sort, page)
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/turbekoff/todo/internal/domain/entities"
+	"github.com/turbekoff/todo/internal/domain/repositories"
+	"github.com/turbekoff/todo/pkg/expr"
+)
+
+func and(conditions ...*repositories.TaskCondition) *repositories.TaskCondition {
+	return &repositories.TaskCondition{Op: repositories.ConditionAnd, Conditions: conditions}
+}
+
+func not(condition *repositories.TaskCondition) *repositories.TaskCondition {
+	return &repositories.TaskCondition{Op: repositories.ConditionNot, Conditions: []*repositories.TaskCondition{condition}}
+}
+
+func TestCompileTerm(t *testing.T) {
+	// Wednesday afternoon.
+	now := time.Date(2024, time.May, 1, 15, 0, 0, 0, time.UTC)
+	noDueDate := taskField(repositories.TaskFieldDueDate, repositories.ConditionEmpty, nil)
+
+	tests := []struct {
+		name string
+		node *expr.Node
+		want *repositories.TaskCondition
+	}{
+		{"tag", &expr.Node{Op: expr.Term, Key: "tag", Value: "Work"},
+			taskField(repositories.TaskFieldTags, repositories.ConditionEqual, "work")},
+		{"key in any case", &expr.Node{Op: expr.Term, Key: "TAG", Value: "work"},
+			taskField(repositories.TaskFieldTags, repositories.ConditionEqual, "work")},
+		{"project", &expr.Node{Op: expr.Term, Key: "project", Value: "42"},
+			taskField(repositories.TaskFieldProject, repositories.ConditionEqual, "42")},
+		{"inbox", &expr.Node{Op: expr.Term, Key: "project", Value: "Inbox"},
+			taskField(repositories.TaskFieldProject, repositories.ConditionEqual, "")},
+		{"status", &expr.Node{Op: expr.Term, Key: "status", Value: "review"},
+			taskField(repositories.TaskFieldStatus, repositories.ConditionEqual, "review")},
+		{"priority", &expr.Node{Op: expr.Term, Key: "priority", Value: "High"},
+			taskField(repositories.TaskFieldPriority, repositories.ConditionEqual, int(entities.PriorityHigh))},
+		{"priority compared", &expr.Node{Op: expr.Term, Key: "priority", Value: ">=high"},
+			taskField(repositories.TaskFieldPriority, repositories.ConditionGreaterOrEqual, int(entities.PriorityHigh))},
+		{"name", &expr.Node{Op: expr.Term, Key: "name", Value: "report"},
+			taskField(repositories.TaskFieldName, repositories.ConditionContains, "report")},
+
+		{"due on", &expr.Node{Op: expr.Term, Key: "due", Value: "2024-05-10"},
+			taskField(repositories.TaskFieldDueDate, repositories.ConditionEqual, "2024-05-10")},
+		{"due after", &expr.Node{Op: expr.Term, Key: "due", Value: ">2024-05-10"},
+			taskField(repositories.TaskFieldDueDate, repositories.ConditionGreater, "2024-05-10")},
+		{"due from", &expr.Node{Op: expr.Term, Key: "due", Value: ">=2024-05-10"},
+			taskField(repositories.TaskFieldDueDate, repositories.ConditionGreaterOrEqual, "2024-05-10")},
+		{"due before leaves out empty dates", &expr.Node{Op: expr.Term, Key: "due", Value: "<2024-05-10"},
+			and(taskField(repositories.TaskFieldDueDate, repositories.ConditionLess, "2024-05-10"), not(noDueDate))},
+		{"due until leaves out empty dates", &expr.Node{Op: expr.Term, Key: "due", Value: "<=2024-05-10"},
+			and(taskField(repositories.TaskFieldDueDate, repositories.ConditionLessOrEqual, "2024-05-10"), not(noDueDate))},
+		{"due none", &expr.Node{Op: expr.Term, Key: "due", Value: "None"}, noDueDate},
+
+		{"completed", &expr.Node{Op: expr.Term, Value: "Completed"},
+			taskField(repositories.TaskFieldCompleted, repositories.ConditionEqual, true)},
+		{"blocked", &expr.Node{Op: expr.Term, Value: "blocked"},
+			taskField(repositories.TaskFieldBlocked, repositories.ConditionEqual, true)},
+		{"overdue", &expr.Node{Op: expr.Term, Value: "overdue"}, and(
+			taskField(repositories.TaskFieldCompleted, repositories.ConditionEqual, false),
+			taskField(repositories.TaskFieldDueAt, repositories.ConditionLess, now),
+		)},
+		{"due today", &expr.Node{Op: expr.Term, Value: "due today"},
+			taskField(repositories.TaskFieldDueDate, repositories.ConditionEqual, "2024-05-01")},
+		{"due tomorrow", &expr.Node{Op: expr.Term, Value: "due tomorrow"},
+			taskField(repositories.TaskFieldDueDate, repositories.ConditionEqual, "2024-05-02")},
+		{"due this week", &expr.Node{Op: expr.Term, Value: "due this week"}, and(
+			taskField(repositories.TaskFieldDueDate, repositories.ConditionGreaterOrEqual, "2024-04-29"),
+			taskField(repositories.TaskFieldDueDate, repositories.ConditionLessOrEqual, "2024-05-05"),
+		)},
+		{"due next week", &expr.Node{Op: expr.Term, Value: "due next week"}, and(
+			taskField(repositories.TaskFieldDueDate, repositories.ConditionGreaterOrEqual, "2024-05-06"),
+			taskField(repositories.TaskFieldDueDate, repositories.ConditionLessOrEqual, "2024-05-12"),
+		)},
+		{"no due date", &expr.Node{Op: expr.Term, Value: "no due date"}, noDueDate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := compileTerm(tt.node, now)
+			if err != nil {
+				t.Fatalf("compileTerm() error: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("compileTerm() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileTermInvalid(t *testing.T) {
+	now := time.Date(2024, time.May, 1, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		node *expr.Node
+	}{
+		{"unknown key", &expr.Node{Op: expr.Term, Key: "color", Value: "red"}},
+		{"unknown phrase", &expr.Node{Op: expr.Term, Value: "due someday"}},
+		{"invalid tag", &expr.Node{Op: expr.Term, Key: "tag", Value: "a b"}},
+		{"empty project", &expr.Node{Op: expr.Term, Key: "project"}},
+		{"empty status", &expr.Node{Op: expr.Term, Key: "status"}},
+		{"empty priority", &expr.Node{Op: expr.Term, Key: "priority", Value: ">="}},
+		{"unknown priority", &expr.Node{Op: expr.Term, Key: "priority", Value: "asap"}},
+		{"invalid due date", &expr.Node{Op: expr.Term, Key: "due", Value: "2024-02-30"}},
+		{"compared due none", &expr.Node{Op: expr.Term, Key: "due", Value: "<none"}},
+		{"empty name", &expr.Node{Op: expr.Term, Key: "name"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := compileTerm(tt.node, now); err == nil {
+				t.Fatalf("compileTerm() = %+v, want an error", got)
+			}
+		})
+	}
+}
+
+func TestCompileQuery(t *testing.T) {
+	got, err := compileQuery("NOT completed AND (tag:work OR due:<2024-05-10)", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := and(
+		not(taskField(repositories.TaskFieldCompleted, repositories.ConditionEqual, true)),
+		&repositories.TaskCondition{Op: repositories.ConditionOr, Conditions: []*repositories.TaskCondition{
+			taskField(repositories.TaskFieldTags, repositories.ConditionEqual, "work"),
+			and(
+				taskField(repositories.TaskFieldDueDate, repositories.ConditionLess, "2024-05-10"),
+				not(taskField(repositories.TaskFieldDueDate, repositories.ConditionEmpty, nil)),
+			),
+		}},
+	)
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("compileQuery() = %+v, want %+v", got, want)
+	}
+}
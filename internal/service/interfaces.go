@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/turbekoff/todo/internal/domain/entities"
+	"github.com/turbekoff/todo/internal/domain/repositories"
 )
 
 type Tokens struct {
@@ -13,23 +14,251 @@ type Tokens struct {
 	RefreshExpireAt time.Time
 }
 
+type UserInput struct {
+	Name     string
+	Password *string // nil keeps the current password
+}
+
+type TaskInput struct {
+	Project   string
+	Parent    string
+	Name      string
+	Notes     string
+	Status    string
+	Completed bool
+	Priority  string
+	Tags      []string
+	DueDate   string
+	DueTime   string
+	TimeZone  string
+
+	Recurrence          string
+	RecurFromCompletion bool
+
+	CompleteSubtasks bool
+
+	BlockedBy []string
+
+	Version *int64
+}
+
+type TaskMoveInput struct {
+	Before string
+	After  string
+
+	Version *int64
+}
+
+type TaskDependency struct {
+	Task      string
+	BlockedBy string
+}
+
+type TaskGraph struct {
+	Tasks []*entities.Task
+	Edges []*TaskDependency
+}
+
+type BoardQuery struct {
+	Project string
+	Limit   int
+}
+
+type BoardColumn struct {
+	Status *entities.WorkflowStatus
+	Tasks  []*entities.Task
+	Total  int
+}
+
+type Board struct {
+	Columns []*BoardColumn
+}
+
+type TaskQuery struct {
+	Project   string
+	Parent    string
+	Due       string
+	TimeZone  string
+	Tags      []string
+	TagMatch  string
+	Sort      string
+	Limit     int
+	Cursor    string
+	WithTotal bool
+	Archived  bool
+	Blocked   *bool
+}
+
+type ProjectInput struct {
+	Name     string
+	Color    string
+	Position *int
+	Archived bool
+}
+
+type TimeEntryInput struct {
+	Start time.Time
+	End   *time.Time // nil keeps a running timer running
+	Note  string
+}
+
+type TimeReportQuery struct {
+	From     string
+	To       string
+	TimeZone string
+}
+
+type TimeTotal struct {
+	Key      string
+	Duration time.Duration
+}
+
+type TimeReport struct {
+	From     string
+	To       string
+	TimeZone string
+	Total    time.Duration
+	Days     []*TimeTotal
+	Tags     []*TimeTotal
+	Projects []*TimeTotal
+}
+
+type TemplateInput struct {
+	Name  string
+	Tasks []*TemplateTaskInput
+}
+
+type TemplateTaskInput struct {
+	Name      string
+	Notes     string
+	Priority  string
+	Tags      []string
+	DueOffset *int // days from the instantiation to the due date
+	DueTime   string
+	Subtasks  []*TemplateTaskInput
+}
+
+type TemplateInstanceInput struct {
+	Project  string
+	Date     string
+	TimeZone string
+	Values   map[string]string
+}
+
+type FilterInput struct {
+	Name     string
+	Query    string
+	TimeZone string
+}
+
+type FilterTaskQuery struct {
+	Sort      string
+	Limit     int
+	Cursor    string
+	WithTotal bool
+}
+
 type UserService interface {
 	Create(name, password string) error
 	Read(id string) (*entities.User, error)
-	Update(id, name, password string) (*entities.User, error)
+	Update(id string, input *UserInput) (*entities.User, error)
+	// Zero days turn automatic archiving off.
+	SetAutoArchive(id string, days int) (*entities.User, error)
+	ReadWorkflow(id string) (*entities.Workflow, error)
+	// A nil workflow brings back the default one.
+	SetWorkflow(id string, workflow *entities.Workflow) (*entities.Workflow, error)
 	Delete(id string) error
 }
 
 type SessionService interface {
-	Create(device, name, password string) (*Tokens, error)
+	Create(device, userAgent, name, password string) (*Tokens, error)
 	Refresh(device string, token string) (*Tokens, error)
-	VerifyAccess(token string) (string, error)
+	VerifyAccess(token string) (string, string, error)
+	Read(id string) (*entities.Session, error)
+	ReadAllByOwner(owner string) ([]*entities.Session, error)
+	Delete(id string) error
+	DeleteAllByOwner(owner string) error
+}
+
+type TaskOperation struct {
+	Op      string
+	IDs     []string
+	Project string
+	Tag     string
+}
+
+type TaskOperationResult struct {
+	ID  string
+	Op  string
+	Err error
 }
 
 type TaskService interface {
-	Create(owner, name string, completed bool) error
+	Create(owner string, input *TaskInput) (*entities.Task, error)
 	Read(id string) (*entities.Task, error)
-	ReadAllByOwner(owner string) ([]*entities.Task, error)
-	Update(id, name string, completed bool) (*entities.Task, error)
+	ReadPage(owner string, query *TaskQuery) (*repositories.TaskPage, error)
+	ReadSubtasks(id string) ([]*entities.Task, error)
+	ReadTags(owner string) ([]*entities.TagUsage, error)
+	ReadBoard(owner string, query *BoardQuery) (*Board, error)
+	ReadDependencies(id string) (*TaskGraph, error)
+	Search(owner, text string, archived bool, limit int) ([]*entities.Task, error)
+	Update(id string, input *TaskInput) (*entities.Task, error)
+	Move(id string, input *TaskMoveInput) (*entities.Task, error)
+	Batch(owner string, operations []*TaskOperation) ([]*TaskOperationResult, error)
+	// A task already in the trash is removed for good.
+	Delete(id string, version *int64) error
+	Purge(id string) error
+	Archive(owner string, days int) (int64, error)
+	AutoArchive() (int64, error)
+	ReadTrash(owner string) ([]*entities.Task, error)
+	// A task whose parent or project is gone is restored to the top level or
+	// the inbox.
+	Restore(id string) (*entities.Task, error)
+	PurgeTrash(retention time.Duration) (int64, error)
+}
+
+type TimeService interface {
+	Start(task, note string) (*entities.TimeEntry, error)
+	Stop(task string) (*entities.TimeEntry, error)
+	Create(task string, input *TimeEntryInput) (*entities.TimeEntry, error)
+	Read(id string) (*entities.TimeEntry, error)
+	ReadAllByTask(task string) ([]*entities.TimeEntry, error)
+	Update(id string, input *TimeEntryInput) (*entities.TimeEntry, error)
 	Delete(id string) error
+	Report(owner string, query *TimeReportQuery) (*TimeReport, error)
+}
+
+type TemplateService interface {
+	Create(owner string, input *TemplateInput) (*entities.Template, error)
+	Read(id string) (*entities.Template, error)
+	ReadAllByOwner(owner string) ([]*entities.Template, error)
+	Update(id string, input *TemplateInput) (*entities.Template, error)
+	Delete(id string) error
+	// Tasks created before a failure are removed for good.
+	Instantiate(id string, input *TemplateInstanceInput) ([]*entities.Task, error)
+}
+
+type FilterService interface {
+	Create(owner string, input *FilterInput) (*entities.Filter, error)
+	Read(id string) (*entities.Filter, error)
+	ReadAllByOwner(owner string) ([]*entities.Filter, error)
+	Update(id string, input *FilterInput) (*entities.Filter, error)
+	Delete(id string) error
+	ReadTasks(id string, query *FilterTaskQuery) (*repositories.TaskPage, error)
+}
+
+type CommentService interface {
+	Create(task, author, body string) (*entities.Comment, error)
+	Read(id string) (*entities.Comment, error)
+	ReadAllByTask(task string) ([]*entities.Comment, error)
+	Update(id, body string) (*entities.Comment, error)
+	Delete(id string) error
+}
+
+type ProjectService interface {
+	Create(owner string, input *ProjectInput) (*entities.Project, error)
+	Read(id string) (*entities.Project, error)
+	ReadAllByOwner(owner string) ([]*entities.Project, error)
+	Update(id string, input *ProjectInput) (*entities.Project, error)
+	Delete(id string, cascade bool) error
 }
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/turbekoff/todo/internal/domain/entities"
+	"github.com/turbekoff/todo/internal/domain/repositories"
+)
+
+const MaxProjectNameLength = 100
+
+var projectColorExpression = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+type projectService struct {
+	userRepository    repositories.UserRepository
+	taskRepository    repositories.TaskRepository
+	projectRepository repositories.ProjectRepository
+}
+
+func NewProjectService(
+	userRepository repositories.UserRepository,
+	taskRepository repositories.TaskRepository,
+	projectRepository repositories.ProjectRepository,
+) ProjectService {
+	return &projectService{
+		userRepository:    userRepository,
+		taskRepository:    taskRepository,
+		projectRepository: projectRepository,
+	}
+}
+
+func (s *projectService) validate(input *ProjectInput) error {
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" {
+		return errors.New("empty name specified")
+	}
+
+	if utf8.RuneCountInString(input.Name) > MaxProjectNameLength {
+		return errors.New("project name is too long")
+	}
+
+	if input.Color != "" && !projectColorExpression.MatchString(input.Color) {
+		return errors.New("color must be formatted as #RRGGBB")
+	}
+
+	if input.Position != nil && *input.Position < 0 {
+		return errors.New("position can't be negative")
+	}
+
+	return nil
+}
+
+func (s *projectService) Create(owner string, input *ProjectInput) (*entities.Project, error) {
+	if err := s.validate(input); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.userRepository.Read(owner); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	project := &entities.Project{
+		Owner:     owner,
+		Name:      input.Name,
+		Color:     strings.ToLower(input.Color),
+		Archived:  input.Archived,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if input.Position != nil {
+		project.Position = *input.Position
+	} else {
+		projects, err := s.projectRepository.ReadAllByOwner(owner)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(projects) > 0 {
+			project.Position = projects[len(projects)-1].Position + 1
+		}
+	}
+
+	if err := s.projectRepository.Create(project); err != nil {
+		return nil, err
+	}
+
+	return project, nil
+}
+
+func (s *projectService) Read(id string) (*entities.Project, error) {
+	return s.projectRepository.Read(id)
+}
+
+func (s *projectService) ReadAllByOwner(owner string) ([]*entities.Project, error) {
+	return s.projectRepository.ReadAllByOwner(owner)
+}
+
+func (s *projectService) Update(id string, input *ProjectInput) (*entities.Project, error) {
+	if err := s.validate(input); err != nil {
+		return nil, err
+	}
+
+	project, err := s.projectRepository.Read(id)
+	if err != nil {
+		return nil, err
+	}
+
+	project.Name = input.Name
+	project.Color = strings.ToLower(input.Color)
+	project.Archived = input.Archived
+	project.UpdatedAt = time.Now()
+
+	if input.Position != nil {
+		project.Position = *input.Position
+	}
+
+	if err := s.projectRepository.Update(project); err != nil {
+		return nil, err
+	}
+
+	return project, nil
+}
+
+func (s *projectService) Delete(id string, cascade bool) error {
+	if cascade {
+		ids, err := s.taskRepository.TrashAllByProject(id, time.Now())
+		if err != nil {
+			return err
+		}
+
+		if err := refreshDependents(s.taskRepository, ids); err != nil {
+			return err
+		}
+	} else {
+		if err := s.taskRepository.MoveAllByProject(id, ""); err != nil {
+			return err
+		}
+	}
+
+	return s.projectRepository.Delete(id)
+}
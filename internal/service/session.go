@@ -11,6 +11,16 @@ import (
 	"github.com/turbekoff/todo/pkg/jwt"
 )
 
+var (
+	ErrRefreshTokenExpired = errors.New("refresh token expired")
+	ErrRefreshTokenReused  = errors.New("refresh token has already been used")
+	ErrDeviceMismatch      = errors.New("device doesn't match")
+	ErrSessionRevoked      = errors.New("session has been revoked")
+	ErrSessionExpired      = errors.New("session expired")
+)
+
+const sessionTouchInterval = time.Minute
+
 type sessionService struct {
 	jwt             *jwt.Manager
 	hasher          hash.Hasher
@@ -37,25 +47,31 @@ func NewSessionService(
 	}
 }
 
-func (s *sessionService) create(owner, device string) (*Tokens, error) {
+func (s *sessionService) tokens(session *entities.Session) (*Tokens, error) {
 	accessExpireAt := time.Now().Add(s.accessTokenTTL)
-	access, err := s.jwt.Generate(owner, s.accessTokenTTL)
+	access, err := s.jwt.Generate(session.Owner, session.ID, s.accessTokenTTL)
 	if err != nil {
 		return nil, err
 	}
 
+	return &Tokens{Access: access, Refresh: session.Token, AccessExpireAt: accessExpireAt, RefreshExpireAt: session.ExpireAt}, nil
+}
+
+func (s *sessionService) create(owner, device, userAgent string) (*Tokens, error) {
 	refresh, err := s.jwt.GenerateRefresh()
 	if err != nil {
 		return nil, err
 	}
 
-	refreshExpireAt := time.Now().Add(s.refreshTokenTTL)
-
+	now := time.Now()
 	session := &entities.Session{
-		Owner:    owner,
-		Device:   device,
-		Token:    refresh,
-		ExpireAt: refreshExpireAt,
+		Owner:      owner,
+		Device:     device,
+		UserAgent:  userAgent,
+		Token:      refresh,
+		CreatedAt:  now,
+		LastUsedAt: now,
+		ExpireAt:   now.Add(s.refreshTokenTTL),
 	}
 
 	if sessions, err := s.sessionRepository.ReadAllByOwner(owner); !errors.Is(err, repositories.ErrSessionNotFound) {
@@ -70,10 +86,10 @@ func (s *sessionService) create(owner, device string) (*Tokens, error) {
 		return nil, err
 	}
 
-	return &Tokens{Access: access, Refresh: refresh, AccessExpireAt: accessExpireAt, RefreshExpireAt: refreshExpireAt}, nil
+	return s.tokens(session)
 }
 
-func (s *sessionService) Create(device, name, password string) (*Tokens, error) {
+func (s *sessionService) Create(device, userAgent, name, password string) (*Tokens, error) {
 	user, err := s.userRepository.ReadByName(name)
 	if err != nil {
 		return nil, err
@@ -83,28 +99,114 @@ func (s *sessionService) Create(device, name, password string) (*Tokens, error)
 		return nil, err
 	}
 
-	return s.create(user.ID, device)
+	return s.create(user.ID, device, userAgent)
 }
 
 func (s *sessionService) Refresh(device string, token string) (*Tokens, error) {
 	session, err := s.sessionRepository.ReadByToken(token)
+	if errors.Is(err, repositories.ErrSessionNotFound) {
+		// A rotated token showing up again means it was copied, so the whole
+		// session is revoked and both holders have to sign in again.
+		if session, err := s.sessionRepository.ReadByPreviousToken(token); err == nil {
+			s.sessionRepository.Delete(session.ID)
+			return nil, ErrRefreshTokenReused
+		}
+		return nil, err
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	s.sessionRepository.Delete(session.ID)
-
 	if time.Now().After(session.ExpireAt) {
-		return nil, errors.New("refresh token expired")
+		s.sessionRepository.Delete(session.ID)
+		return nil, ErrRefreshTokenExpired
 	}
 
 	if session.Device != device {
-		return nil, errors.New("device doesn't match")
+		s.sessionRepository.Delete(session.ID)
+		return nil, ErrDeviceMismatch
+	}
+
+	refresh, err := s.jwt.GenerateRefresh()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session.PreviousToken = session.Token
+	session.Token = refresh
+	session.LastUsedAt = now
+	session.ExpireAt = now.Add(s.refreshTokenTTL)
+
+	// Concurrent refreshes with the same token are reuse as well, only the
+	// first one rotates it.
+	if err := s.sessionRepository.Rotate(session); err != nil {
+		if errors.Is(err, repositories.ErrSessionConflict) {
+			s.sessionRepository.Delete(session.ID)
+			return nil, ErrRefreshTokenReused
+		}
+		return nil, err
 	}
 
-	return s.create(session.Owner, device)
+	return s.tokens(session)
 }
 
-func (s *sessionService) VerifyAccess(token string) (string, error) {
-	return s.jwt.Parse(token)
+func (s *sessionService) VerifyAccess(token string) (string, string, error) {
+	owner, id, err := s.jwt.Parse(token)
+	if err != nil {
+		return "", "", err
+	}
+
+	session, err := s.sessionRepository.Read(id)
+	if errors.Is(err, repositories.ErrSessionNotFound) {
+		return "", "", ErrSessionRevoked
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	if session.Owner != owner {
+		return "", "", ErrSessionRevoked
+	}
+
+	now := time.Now()
+	if now.After(session.ExpireAt) {
+		s.sessionRepository.Delete(session.ID)
+		return "", "", ErrSessionExpired
+	}
+
+	if now.Sub(session.LastUsedAt) > sessionTouchInterval {
+		if err := s.sessionRepository.Touch(session.ID, now); err != nil {
+			return "", "", err
+		}
+	}
+
+	return owner, session.ID, nil
+}
+
+func (s *sessionService) Read(id string) (*entities.Session, error) {
+	return s.sessionRepository.Read(id)
+}
+
+func (s *sessionService) ReadAllByOwner(owner string) ([]*entities.Session, error) {
+	return s.sessionRepository.ReadAllByOwner(owner)
+}
+
+func (s *sessionService) Delete(id string) error {
+	return s.sessionRepository.Delete(id)
+}
+
+func (s *sessionService) DeleteAllByOwner(owner string) error {
+	sessions, err := s.sessionRepository.ReadAllByOwner(owner)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		if err := s.sessionRepository.Delete(session.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
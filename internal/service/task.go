@@ -2,76 +2,1448 @@ package service
 
 import (
 	"errors"
+	"fmt"
+	"regexp"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/turbekoff/todo/internal/domain/entities"
 	"github.com/turbekoff/todo/internal/domain/repositories"
+	"github.com/turbekoff/todo/pkg/rank"
+	"github.com/turbekoff/todo/pkg/rrule"
+)
+
+const (
+	DueOverdue = "overdue"
+	DueToday   = "today"
+	DueWeek    = "week"
+	DueNone    = "none"
+)
+
+const (
+	TagMatchAll = "all"
+	TagMatchAny = "any"
+)
+
+const (
+	MaxTaskTags  = 20
+	MaxTagLength = 32
+)
+
+const (
+	DefaultTaskPageLimit = 50
+	MaxTaskPageLimit     = 200
+)
+
+const MaxArchiveDays = 3650
+
+const MaxTaskNotesLength = 10000
+
+const (
+	dueDateLayout = "2006-01-02"
+	dueTimeLayout = "15:04"
+)
+
+var tagExpression = regexp.MustCompile(`^[\p{L}\p{N}_-]+$`)
+
+var taskSortFields = []string{
+	repositories.TaskSortPriority,
+	repositories.TaskSortCreatedAt,
+	repositories.TaskSortUpdatedAt,
+	repositories.TaskSortDueAt,
+	repositories.TaskSortName,
+	repositories.TaskSortPosition,
+}
+
+const (
+	ProjectInbox = "inbox"
+	ParentNone   = "none"
+)
+
+const (
+	TaskOperationComplete   = "complete"
+	TaskOperationUncomplete = "uncomplete"
+	TaskOperationDelete     = "delete"
+	TaskOperationMove       = "move"
+	TaskOperationAddTag     = "addTag"
+)
+
+const MaxBatchTasks = 500
+
+const (
+	MaxTaskBlockers  = 20
+	MaxTaskGraphSize = 200
+)
+
+const MaxTaskDepth = 3
+
+var (
+	ErrProjectAuthorization = errors.New("project belongs to another user")
+	ErrParentAuthorization  = errors.New("parent task belongs to another user")
+	ErrTaskCycle            = errors.New("task can't be a subtask of itself")
+	ErrTaskDepth            = fmt.Errorf("subtasks can't be nested deeper than %d levels", MaxTaskDepth)
+	ErrTaskNotesLength      = fmt.Errorf("notes can't be longer than %d characters", MaxTaskNotesLength)
+	ErrTaskTrashed          = errors.New("task is in the trash")
+	ErrTaskNotTrashed       = errors.New("task isn't in the trash")
+	ErrTaskTags             = fmt.Errorf("task can't have more than %d tags", MaxTaskTags)
+	ErrAnchorAuthorization  = errors.New("anchor task belongs to another user")
+	ErrBlockerAuthorization = errors.New("blocking task belongs to another user")
+	ErrTaskDependencyCycle  = errors.New("task can't depend on itself")
+	ErrTaskBlockers         = fmt.Errorf("task can't be blocked by more than %d tasks", MaxTaskBlockers)
+	ErrTaskTransition       = errors.New("workflow doesn't allow the task to move to this status")
 )
 
 type taskService struct {
-	userRepository repositories.UserRepository
-	taskRepository repositories.TaskRepository
+	userRepository    repositories.UserRepository
+	taskRepository    repositories.TaskRepository
+	projectRepository repositories.ProjectRepository
+
+	timeEntryRepository repositories.TimeEntryRepository
+	commentRepository   repositories.CommentRepository
 }
 
 func NewTaskService(
 	userRepository repositories.UserRepository,
 	taskRepository repositories.TaskRepository,
+	projectRepository repositories.ProjectRepository,
+	timeEntryRepository repositories.TimeEntryRepository,
+	commentRepository repositories.CommentRepository,
 ) TaskService {
 	return &taskService{
-		userRepository: userRepository,
-		taskRepository: taskRepository,
+		userRepository:    userRepository,
+		taskRepository:    taskRepository,
+		projectRepository: projectRepository,
+
+		timeEntryRepository: timeEntryRepository,
+		commentRepository:   commentRepository,
+	}
+}
+
+func (s *taskService) deleteRelated(ids []string) error {
+	if err := s.timeEntryRepository.DeleteAllByTask(ids); err != nil {
+		return err
 	}
+	return s.commentRepository.DeleteAllByTask(ids)
 }
 
-func (s *taskService) Create(owner, name string, completed bool) error {
-	name = strings.TrimSpace(name)
+func location(name string) (*time.Location, error) {
 	if name == "" {
-		return errors.New("empty name specified")
+		return time.UTC, nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, errors.New("unknown time zone " + name)
+	}
+	return loc, nil
+}
+
+func taskSort(s string) (*repositories.TaskSort, error) {
+	if s == "" {
+		return &repositories.TaskSort{Field: repositories.TaskSortCreatedAt}, nil
+	}
+
+	sort := &repositories.TaskSort{Field: strings.TrimPrefix(s, "-"), Descending: strings.HasPrefix(s, "-")}
+	for _, field := range taskSortFields {
+		if field == sort.Field {
+			return sort, nil
+		}
+	}
+	return nil, errors.New("sort must be one of " + strings.Join(taskSortFields, ", "))
+}
+
+func normalizeTags(tags []string) ([]string, error) {
+	var result []string
+	seen := map[string]bool{}
+
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if utf8.RuneCountInString(tag) > MaxTagLength || !tagExpression.MatchString(tag) {
+			return nil, fmt.Errorf("tag must consist of 1-%d letters, digits, dashes and underscores", MaxTagLength)
+		}
+
+		if !seen[tag] {
+			seen[tag] = true
+			result = append(result, tag)
+		}
+	}
+
+	if len(result) > MaxTaskTags {
+		return nil, ErrTaskTags
+	}
+	return result, nil
+}
+
+func (s *taskService) setProject(task *entities.Task, input *TaskInput) error {
+	if input.Project != "" {
+		project, err := s.projectRepository.Read(input.Project)
+		if err != nil {
+			return err
+		}
+
+		if project.Owner != task.Owner {
+			return ErrProjectAuthorization
+		}
+	}
+
+	task.Project = input.Project
+	return nil
+}
+
+func (s *taskService) height(id string, limit int) (int, error) {
+	if limit == 0 {
+		return 0, ErrTaskDepth
+	}
+
+	subtasks, err := s.taskRepository.ReadAllByParent(id)
+	if err != nil {
+		return 0, err
+	}
+
+	height := 1
+	for _, subtask := range subtasks {
+		h, err := s.height(subtask.ID, limit-1)
+		if err != nil {
+			return 0, err
+		}
+		if h+1 > height {
+			height = h + 1
+		}
+	}
+	return height, nil
+}
+
+func (s *taskService) setParent(task *entities.Task, parent string) error {
+	if parent == task.Parent {
+		return nil
+	}
+
+	if parent == "" {
+		task.Parent = ""
+		return nil
+	}
+
+	levels := 0
+	for id := parent; id != ""; levels++ {
+		if id == task.ID {
+			return ErrTaskCycle
+		}
+
+		if levels == MaxTaskDepth {
+			return ErrTaskDepth
+		}
+
+		ancestor, err := s.taskRepository.Read(id)
+		if err != nil {
+			return err
+		}
+
+		if ancestor.DeletedAt != nil {
+			return ErrTaskTrashed
+		}
+
+		if ancestor.Owner != task.Owner {
+			return ErrParentAuthorization
+		}
+		id = ancestor.Parent
+	}
+
+	height := 1
+	if task.ID != "" {
+		var err error
+		if height, err = s.height(task.ID, MaxTaskDepth-levels); err != nil {
+			return err
+		}
+	}
+
+	if levels+height > MaxTaskDepth {
+		return ErrTaskDepth
+	}
+
+	task.Parent = parent
+	return nil
+}
+
+// Blockers the task already had may be in the trash, the ones removed for good
+// are dropped.
+func (s *taskService) setBlockedBy(task *entities.Task, ids []string) error {
+	current := map[string]bool{}
+	for _, id := range task.BlockedBy {
+		current[id] = true
+	}
+
+	var unique []string
+	seen := map[string]bool{}
+
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	if len(unique) > MaxTaskBlockers {
+		return ErrTaskBlockers
+	}
+
+	var blockers []string
+	blocked := false
+
+	for _, id := range unique {
+		if id == task.ID {
+			return ErrTaskDependencyCycle
+		}
+
+		blocker, err := s.taskRepository.Read(id)
+		if errors.Is(err, repositories.ErrTaskNotFound) && current[id] {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if blocker.Owner != task.Owner {
+			return ErrBlockerAuthorization
+		}
+
+		if blocker.DeletedAt != nil && !current[id] {
+			return ErrTaskTrashed
+		}
+
+		if blocker.DeletedAt == nil && !blocker.Completed {
+			blocked = true
+		}
+		blockers = append(blockers, id)
+	}
+
+	if task.ID != "" {
+		if err := s.checkDependencyCycle(task.ID, blockers); err != nil {
+			return err
+		}
+	}
+
+	task.BlockedBy = blockers
+	task.Blocked = blocked
+	return nil
+}
+
+// Tasks in the trash are walked as well, since they can be restored.
+func (s *taskService) checkDependencyCycle(id string, blockers []string) error {
+	visited := map[string]bool{}
+	stack := append([]string(nil), blockers...)
+
+	for len(stack) > 0 {
+		next := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if next == id {
+			return ErrTaskDependencyCycle
+		}
+
+		if visited[next] {
+			continue
+		}
+		visited[next] = true
+
+		task, err := s.taskRepository.Read(next)
+		if errors.Is(err, repositories.ErrTaskNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		stack = append(stack, task.BlockedBy...)
+	}
+	return nil
+}
+
+func refreshDependents(taskRepository repositories.TaskRepository, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	dependents, err := taskRepository.ReadDependents(ids)
+	if err != nil {
+		return err
+	}
+
+	for _, dependent := range dependents {
+		blockers, err := taskRepository.ReadMany(dependent.Owner, dependent.BlockedBy)
+		if err != nil {
+			return err
+		}
+
+		blocked := false
+		for _, blocker := range blockers {
+			if !blocker.Completed {
+				blocked = true
+				break
+			}
+		}
+
+		if blocked != dependent.Blocked {
+			if err := taskRepository.SetBlocked(dependent.ID, blocked); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *taskService) subtree(id string) ([]string, error) {
+	ids := []string{id}
+
+	subtasks, err := s.taskRepository.ReadAllByParent(id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, subtask := range subtasks {
+		more, err := s.subtree(subtask.ID)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, more...)
+	}
+	return ids, nil
+}
+
+func (s *taskService) workflow(owner string) (*entities.Workflow, error) {
+	user, err := s.userRepository.Read(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Workflow == nil {
+		return entities.DefaultWorkflow(), nil
+	}
+	return user.Workflow, nil
+}
+
+// completionStatus returns nil when the workflow doesn't allow the task to be
+// completed or reopened.
+func completionStatus(workflow *entities.Workflow, task *entities.Task, completed bool) *entities.WorkflowStatus {
+	current := workflow.Resolve(task.Status, task.Completed)
+	if current.Done == completed {
+		return current
+	}
+
+	target := workflow.Initial()
+	if completed {
+		target = workflow.Final()
+	}
+
+	if !workflow.CanMove(current.Key, target.Key) {
+		return nil
+	}
+	return target
+}
+
+// New tasks are created in any status.
+func setStatus(task *entities.Task, workflow *entities.Workflow, input *TaskInput) (bool, error) {
+	current := workflow.Resolve(task.Status, task.Completed)
+	target := current
+
+	switch {
+	case input.Status != "" && input.Status != current.Key:
+		if target = workflow.Status(input.Status); target == nil {
+			var keys []string
+			for _, status := range workflow.Statuses {
+				keys = append(keys, status.Key)
+			}
+			return false, errors.New("status must be one of " + strings.Join(keys, ", "))
+		}
+
+		if task.ID != "" && !workflow.CanMove(current.Key, target.Key) {
+			return false, ErrTaskTransition
+		}
+	case input.Completed != current.Done:
+		if task.ID == "" {
+			target = workflow.Final()
+		} else if target = completionStatus(workflow, task, input.Completed); target == nil {
+			return false, ErrTaskTransition
+		}
+	}
+
+	task.Status = target.Key
+	return target.Done, nil
+}
+
+// Reopening a task takes it out of the archive.
+func setCompleted(task *entities.Task, completed bool, now time.Time) {
+	if completed && !task.Completed {
+		task.CompletedAt = &now
+	}
+
+	if !completed {
+		task.CompletedAt = nil
+		task.Archived = false
+	}
+
+	task.Completed = completed
+}
+
+func (s *taskService) completeSubtasks(id string, workflow *entities.Workflow, now time.Time) error {
+	subtasks, err := s.taskRepository.ReadAllByParent(id)
+	if err != nil {
+		return err
+	}
+
+	for _, subtask := range subtasks {
+		if status := completionStatus(workflow, subtask, true); !subtask.Completed && status != nil {
+			subtask.Status = status.Key
+			setCompleted(subtask, true, now)
+			subtask.UpdatedAt = now
+
+			if err := s.taskRepository.Update(subtask); err != nil {
+				return err
+			}
+
+			if err := refreshDependents(s.taskRepository, []string{subtask.ID}); err != nil {
+				return err
+			}
+		}
+
+		if err := s.completeSubtasks(subtask.ID, workflow, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// A task without a due time is due by the end of its due day.
+func (s *taskService) setDue(task *entities.Task, input *TaskInput) error {
+	if input.DueDate == "" {
+		if input.DueTime != "" {
+			return errors.New("due time specified without due date")
+		}
+
+		task.DueDate = ""
+		task.DueTime = ""
+		task.TimeZone = ""
+		task.DueAt = nil
+		return nil
+	}
+
+	loc, err := location(input.TimeZone)
+	if err != nil {
+		return err
 	}
 
-	_, err := s.userRepository.Read(owner)
+	date, err := time.ParseInLocation(dueDateLayout, input.DueDate, loc)
 	if err != nil {
+		return errors.New("due date must be formatted as YYYY-MM-DD")
+	}
+
+	dueAt := date.AddDate(0, 0, 1)
+	if input.DueTime != "" {
+		clock, err := time.Parse(dueTimeLayout, input.DueTime)
+		if err != nil {
+			return errors.New("due time must be formatted as HH:MM")
+		}
+		dueAt = time.Date(date.Year(), date.Month(), date.Day(), clock.Hour(), clock.Minute(), 0, 0, loc)
+	}
+
+	task.DueDate = input.DueDate
+	task.DueTime = input.DueTime
+	task.TimeZone = loc.String()
+	task.DueAt = &dueAt
+	return nil
+}
+
+func (s *taskService) setRecurrence(task *entities.Task, input *TaskInput) error {
+	if strings.TrimSpace(input.Recurrence) == "" {
+		if input.RecurFromCompletion {
+			return errors.New("recurrence from completion specified without recurrence")
+		}
+
+		task.Recurrence = ""
+		task.RecurFromCompletion = false
 		return nil
 	}
 
+	rule, err := rrule.Parse(input.Recurrence)
+	if err != nil {
+		return err
+	}
+
+	if task.DueDate == "" {
+		return errors.New("recurrence specified without due date")
+	}
+
+	task.Recurrence = rule.String()
+	task.RecurFromCompletion = input.RecurFromCompletion
+	return nil
+}
+
+// recur returns the completed copy to keep in history, or nil when the series
+// has ended and the task stays completed.
+func (s *taskService) recur(task *entities.Task, workflow *entities.Workflow, now time.Time) (*entities.Task, error) {
+	rule, err := rrule.Parse(task.Recurrence)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := location(task.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+
+	start, err := time.ParseInLocation(dueDateLayout, task.DueDate, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	if task.DueTime != "" {
+		clock, err := time.Parse(dueTimeLayout, task.DueTime)
+		if err != nil {
+			return nil, err
+		}
+		start = time.Date(start.Year(), start.Month(), start.Day(), clock.Hour(), clock.Minute(), 0, 0, loc)
+	}
+
+	// Occurrences are compared by day, the series moves past today even if
+	// the task is completed before its due time.
+	now = now.In(loc)
+	after := time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 0, loc)
+	if task.RecurFromCompletion {
+		start = time.Date(now.Year(), now.Month(), now.Day(), start.Hour(), start.Minute(), 0, 0, loc)
+	} else if start.After(after) {
+		after = time.Date(start.Year(), start.Month(), start.Day(), 23, 59, 59, 0, loc)
+	}
+
+	next, ok := rule.Next(start, after)
+	if !ok {
+		return nil, nil
+	}
+
+	history := *task
+	history.ID = ""
+	history.Recurrence = ""
+	history.RecurFromCompletion = false
+
+	task.Recurrence = rule.Rest(start, next).String()
+	task.Status = workflow.Initial().Key
+	setCompleted(task, false, now)
+	if err := s.setDue(task, &TaskInput{DueDate: next.Format(dueDateLayout), DueTime: task.DueTime, TimeZone: task.TimeZone}); err != nil {
+		return nil, err
+	}
+	return &history, nil
+}
+
+func (s *taskService) Create(owner string, input *TaskInput) (*entities.Task, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, errors.New("empty name specified")
+	}
+
+	if utf8.RuneCountInString(input.Notes) > MaxTaskNotesLength {
+		return nil, ErrTaskNotesLength
+	}
+
+	priority, err := entities.ParsePriority(input.Priority)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := normalizeTags(input.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	workflow, err := s.workflow(owner)
+	if err != nil {
+		return nil, err
+	}
+
 	now := time.Now()
 	task := &entities.Task{
 		Owner:     owner,
 		Name:      name,
-		Completed: completed,
+		Notes:     input.Notes,
+		Priority:  priority,
+		Tags:      tags,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
 
-	return s.taskRepository.Create(task)
-}
+	completed, err := setStatus(task, workflow, input)
+	if err != nil {
+		return nil, err
+	}
+	setCompleted(task, completed, now)
 
-func (s *taskService) Read(id string) (*entities.Task, error) {
-	return s.taskRepository.Read(id)
-}
+	if err := s.setProject(task, input); err != nil {
+		return nil, err
+	}
 
-func (s *taskService) ReadAllByOwner(owner string) ([]*entities.Task, error) {
-	return s.taskRepository.ReadAllByOwner(owner)
-}
+	if err := s.setParent(task, input.Parent); err != nil {
+		return nil, err
+	}
 
-func (s *taskService) Update(id, name string, completed bool) (*entities.Task, error) {
-	task, err := s.taskRepository.Read(id)
-	if err != nil {
+	if err := s.setDue(task, input); err != nil {
 		return nil, err
 	}
 
-	task.Name = name
-	task.Completed = completed
-	task.UpdatedAt = time.Now()
+	if err := s.setRecurrence(task, input); err != nil {
+		return nil, err
+	}
 
-	if err = s.taskRepository.Update(task); err != nil {
+	if err := s.setBlockedBy(task, input.BlockedBy); err != nil {
+		return nil, err
+	}
+
+	if task.Position, err = s.lastPosition(owner); err != nil {
+		return nil, err
+	}
+
+	if err := s.taskRepository.Create(task); err != nil {
 		return nil, err
 	}
 
 	return task, nil
 }
 
-func (s *taskService) Delete(id string) error {
-	return s.taskRepository.Delete(id)
+func (s *taskService) lastPosition(owner string) (string, error) {
+	last, err := s.taskRepository.ReadAdjacent(owner, "", true, "")
+	if errors.Is(err, repositories.ErrTaskNotFound) {
+		return rank.After("")
+	}
+	if err != nil {
+		return "", err
+	}
+	return rank.After(last.Position)
+}
+
+func (s *taskService) Read(id string) (*entities.Task, error) {
+	return s.taskRepository.Read(id)
+}
+
+func (s *taskService) ReadPage(owner string, query *TaskQuery) (*repositories.TaskPage, error) {
+	filter := &repositories.TaskFilter{Owner: owner, Archived: query.Archived, Blocked: query.Blocked}
+
+	sort, err := taskSort(query.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &repositories.Page{Limit: query.Limit, Cursor: query.Cursor, WithTotal: query.WithTotal}
+	switch {
+	case page.Limit == 0:
+		page.Limit = DefaultTaskPageLimit
+	case page.Limit < 0 || page.Limit > MaxTaskPageLimit:
+		return nil, fmt.Errorf("limit must be between 1 and %d", MaxTaskPageLimit)
+	}
+
+	loc, err := location(query.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	switch query.Parent {
+	case "":
+	case ParentNone:
+		filter.Parent = new(string)
+	default:
+		filter.Parent = &query.Parent
+	}
+
+	switch query.Project {
+	case "":
+	case ProjectInbox:
+		filter.Project = new(string)
+	default:
+		filter.Project = &query.Project
+	}
+
+	if filter.Tags, err = normalizeTags(query.Tags); err != nil {
+		return nil, err
+	}
+
+	switch query.TagMatch {
+	case "", TagMatchAll:
+		filter.AllTags = true
+	case TagMatchAny:
+	default:
+		return nil, errors.New("tag match must be either all or any")
+	}
+
+	switch query.Due {
+	case "":
+	case DueOverdue:
+		completed := false
+		filter.Completed = &completed
+		filter.DueBefore = &now
+	case DueToday:
+		filter.DueFrom = today.Format(dueDateLayout)
+		filter.DueTo = filter.DueFrom
+	case DueWeek:
+		monday := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+		filter.DueFrom = monday.Format(dueDateLayout)
+		filter.DueTo = monday.AddDate(0, 0, 6).Format(dueDateLayout)
+	case DueNone:
+		filter.WithoutDue = true
+	default:
+		return nil, errors.New("due filter must be one of overdue, today, week or none")
+	}
+
+	return s.taskRepository.ReadPage(filter, sort, page)
+}
+
+func (s *taskService) ReadSubtasks(id string) ([]*entities.Task, error) {
+	return s.taskRepository.ReadAllByParent(id)
+}
+
+func (s *taskService) ReadTags(owner string) ([]*entities.TagUsage, error) {
+	return s.taskRepository.ReadTags(owner)
+}
+
+func (s *taskService) Move(id string, input *TaskMoveInput) (*entities.Task, error) {
+	if (input.Before == "") == (input.After == "") {
+		return nil, errors.New("either before or after must be specified")
+	}
+
+	before := input.Before != ""
+	anchorID := input.After
+	if before {
+		anchorID = input.Before
+	}
+
+	if anchorID == id {
+		return nil, errors.New("task can't be moved next to itself")
+	}
+
+	task, err := s.taskRepository.Read(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if task.DeletedAt != nil {
+		return nil, ErrTaskTrashed
+	}
+
+	if input.Version != nil && *input.Version != task.Version {
+		return nil, repositories.ErrTaskConflict
+	}
+
+	anchor, err := s.taskRepository.Read(anchorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if anchor.Owner != task.Owner {
+		return nil, ErrAnchorAuthorization
+	}
+
+	if anchor.DeletedAt != nil {
+		return nil, ErrTaskTrashed
+	}
+
+	now := time.Now()
+
+	// Tasks created before ordering get a position at the end of the list
+	// once they are used as an anchor.
+	if anchor.Position == "" {
+		if anchor.Position, err = s.lastPosition(anchor.Owner); err != nil {
+			return nil, err
+		}
+		anchor.UpdatedAt = now
+
+		if err := s.taskRepository.Update(anchor); err != nil {
+			return nil, err
+		}
+	}
+
+	var low, high string
+	adjacent, err := s.taskRepository.ReadAdjacent(task.Owner, anchor.Position, before, task.ID)
+	if err != nil && !errors.Is(err, repositories.ErrTaskNotFound) {
+		return nil, err
+	}
+
+	if before {
+		high = anchor.Position
+		if adjacent != nil {
+			low = adjacent.Position
+		}
+	} else {
+		low = anchor.Position
+		if adjacent != nil {
+			high = adjacent.Position
+		}
+	}
+
+	if task.Position, err = rank.Between(low, high); err != nil {
+		return nil, err
+	}
+	task.UpdatedAt = now
+
+	if err := s.taskRepository.Update(task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+func (s *taskService) ReadBoard(owner string, query *BoardQuery) (*Board, error) {
+	switch {
+	case query.Limit == 0:
+		query.Limit = DefaultTaskPageLimit
+	case query.Limit < 0 || query.Limit > MaxTaskPageLimit:
+		return nil, fmt.Errorf("limit must be between 1 and %d", MaxTaskPageLimit)
+	}
+
+	workflow, err := s.workflow(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &repositories.TaskFilter{Owner: owner}
+	switch query.Project {
+	case "":
+	case ProjectInbox:
+		filter.Project = new(string)
+	default:
+		filter.Project = &query.Project
+	}
+
+	board := &Board{}
+	for _, status := range workflow.Statuses {
+		filter.Condition = boardColumn(workflow, status)
+		page, err := s.taskRepository.ReadPage(
+			filter,
+			&repositories.TaskSort{Field: repositories.TaskSortPosition},
+			&repositories.Page{Limit: query.Limit, WithTotal: true},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		board.Columns = append(board.Columns, &BoardColumn{Status: status, Tasks: page.Tasks, Total: int(*page.Total)})
+	}
+
+	return board, nil
+}
+
+// The initial and final statuses also take the tasks in statuses missing from
+// the workflow.
+func boardColumn(workflow *entities.Workflow, status *entities.WorkflowStatus) *repositories.TaskCondition {
+	column := &repositories.TaskCondition{Op: repositories.ConditionOr, Conditions: []*repositories.TaskCondition{
+		{Op: repositories.ConditionAnd, Conditions: []*repositories.TaskCondition{
+			taskField(repositories.TaskFieldStatus, repositories.ConditionEqual, status.Key),
+			taskField(repositories.TaskFieldCompleted, repositories.ConditionEqual, status.Done),
+		}},
+	}}
+
+	if status != workflow.Initial() && status != workflow.Final() {
+		return column
+	}
+
+	known := &repositories.TaskCondition{Op: repositories.ConditionOr}
+	for _, other := range workflow.Statuses {
+		if other.Done == status.Done {
+			known.Conditions = append(known.Conditions, taskField(repositories.TaskFieldStatus, repositories.ConditionEqual, other.Key))
+		}
+	}
+
+	column.Conditions = append(column.Conditions, &repositories.TaskCondition{Op: repositories.ConditionAnd, Conditions: []*repositories.TaskCondition{
+		taskField(repositories.TaskFieldCompleted, repositories.ConditionEqual, status.Done),
+		{Op: repositories.ConditionNot, Conditions: []*repositories.TaskCondition{known}},
+	}})
+	return column
+}
+
+func walkDependencies(graph *TaskGraph, found map[string]bool, task *entities.Task, next func([]*entities.Task) ([]*entities.Task, error)) error {
+	level := []*entities.Task{task}
+
+	for len(level) > 0 && len(graph.Tasks) < MaxTaskGraphSize {
+		tasks, err := next(level)
+		if err != nil {
+			return err
+		}
+
+		level = nil
+		for _, t := range tasks {
+			if found[t.ID] || t.Owner != task.Owner || t.DeletedAt != nil || len(graph.Tasks) == MaxTaskGraphSize {
+				continue
+			}
+
+			found[t.ID] = true
+			graph.Tasks = append(graph.Tasks, t)
+			level = append(level, t)
+		}
+	}
+	return nil
+}
+
+func (s *taskService) ReadDependencies(id string) (*TaskGraph, error) {
+	task, err := s.taskRepository.Read(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if task.DeletedAt != nil {
+		return nil, ErrTaskTrashed
+	}
+
+	graph := &TaskGraph{Tasks: []*entities.Task{task}}
+	found := map[string]bool{task.ID: true}
+
+	err = walkDependencies(graph, found, task, func(level []*entities.Task) ([]*entities.Task, error) {
+		var ids []string
+		for _, t := range level {
+			ids = append(ids, t.BlockedBy...)
+		}
+
+		if len(ids) == 0 {
+			return nil, nil
+		}
+		return s.taskRepository.ReadMany(task.Owner, ids)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = walkDependencies(graph, found, task, func(level []*entities.Task) ([]*entities.Task, error) {
+		var ids []string
+		for _, t := range level {
+			ids = append(ids, t.ID)
+		}
+		return s.taskRepository.ReadDependents(ids)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range graph.Tasks {
+		for _, blocker := range t.BlockedBy {
+			if found[blocker] {
+				graph.Edges = append(graph.Edges, &TaskDependency{Task: t.ID, BlockedBy: blocker})
+			}
+		}
+	}
+	return graph, nil
+}
+
+func (s *taskService) Search(owner, text string, archived bool, limit int) ([]*entities.Task, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, errors.New("empty search query specified")
+	}
+
+	switch {
+	case limit == 0:
+		limit = DefaultTaskPageLimit
+	case limit < 0 || limit > MaxTaskPageLimit:
+		return nil, fmt.Errorf("limit must be between 1 and %d", MaxTaskPageLimit)
+	}
+
+	return s.taskRepository.Search(owner, text, archived, limit)
+}
+
+func (s *taskService) Update(id string, input *TaskInput) (*entities.Task, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, errors.New("empty name specified")
+	}
+
+	if utf8.RuneCountInString(input.Notes) > MaxTaskNotesLength {
+		return nil, ErrTaskNotesLength
+	}
+
+	priority, err := entities.ParsePriority(input.Priority)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := normalizeTags(input.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := s.taskRepository.Read(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if task.DeletedAt != nil {
+		return nil, ErrTaskTrashed
+	}
+
+	if input.Version != nil && *input.Version != task.Version {
+		return nil, repositories.ErrTaskConflict
+	}
+
+	if err := s.setProject(task, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.setParent(task, input.Parent); err != nil {
+		return nil, err
+	}
+
+	if err := s.setDue(task, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.setRecurrence(task, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.setBlockedBy(task, input.BlockedBy); err != nil {
+		return nil, err
+	}
+
+	workflow, err := s.workflow(task.Owner)
+	if err != nil {
+		return nil, err
+	}
+
+	done, err := setStatus(task, workflow, input)
+	if err != nil {
+		return nil, err
+	}
+
+	completed := !task.Completed && done
+	wasCompleted := task.Completed
+
+	task.Name = name
+	task.Notes = input.Notes
+	task.Priority = priority
+	task.Tags = tags
+	task.UpdatedAt = time.Now()
+	setCompleted(task, done, task.UpdatedAt)
+
+	var history *entities.Task
+	if completed && task.Recurrence != "" {
+		if history, err = s.recur(task, workflow, task.UpdatedAt); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = s.taskRepository.Update(task); err != nil {
+		return nil, err
+	}
+
+	if history != nil {
+		if err := s.taskRepository.Create(history); err != nil {
+			return nil, err
+		}
+	}
+
+	if task.Completed != wasCompleted {
+		if err := refreshDependents(s.taskRepository, []string{task.ID}); err != nil {
+			return nil, err
+		}
+	}
+
+	if task.Completed && input.CompleteSubtasks {
+		if err := s.completeSubtasks(task.ID, workflow, task.UpdatedAt); err != nil {
+			return nil, err
+		}
+	}
+
+	return task, nil
+}
+
+func (s *taskService) Delete(id string, version *int64) error {
+	task, err := s.taskRepository.Read(id)
+	if err != nil {
+		return err
+	}
+
+	if version != nil && *version != task.Version {
+		return repositories.ErrTaskConflict
+	}
+
+	if task.DeletedAt != nil {
+		return s.Purge(id)
+	}
+
+	ids, err := s.subtree(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.taskRepository.Trash(id, time.Now()); err != nil {
+		return err
+	}
+	return refreshDependents(s.taskRepository, ids)
+}
+
+func (s *taskService) Purge(id string) error {
+	ids, err := s.taskRepository.Delete(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.deleteRelated(ids); err != nil {
+		return err
+	}
+	return refreshDependents(s.taskRepository, ids)
+}
+
+func (s *taskService) Archive(owner string, days int) (int64, error) {
+	if days < 0 || days > MaxArchiveDays {
+		return 0, fmt.Errorf("days must be between 0 and %d", MaxArchiveDays)
+	}
+
+	return s.taskRepository.Archive(owner, time.Now().AddDate(0, 0, -days))
+}
+
+func (s *taskService) AutoArchive() (int64, error) {
+	users, err := s.userRepository.ReadAllWithAutoArchive()
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, user := range users {
+		count, err := s.taskRepository.Archive(user.ID, time.Now().AddDate(0, 0, -user.AutoArchiveDays))
+		if err != nil {
+			return total, err
+		}
+		total += count
+	}
+	return total, nil
+}
+
+func (s *taskService) ReadTrash(owner string) ([]*entities.Task, error) {
+	return s.taskRepository.ReadTrash(owner)
+}
+
+func (s *taskService) Restore(id string) (*entities.Task, error) {
+	task, err := s.taskRepository.Read(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if task.DeletedAt == nil {
+		return nil, ErrTaskNotTrashed
+	}
+
+	if err := s.taskRepository.Restore(id); err != nil {
+		return nil, err
+	}
+
+	if task, err = s.taskRepository.Read(id); err != nil {
+		return nil, err
+	}
+
+	detached := false
+	if task.Parent != "" {
+		parent, err := s.taskRepository.Read(task.Parent)
+		if err != nil && !errors.Is(err, repositories.ErrTaskNotFound) {
+			return nil, err
+		}
+
+		if err != nil || parent.DeletedAt != nil {
+			task.Parent = ""
+			detached = true
+		}
+	}
+
+	if task.Project != "" {
+		_, err := s.projectRepository.Read(task.Project)
+		if err != nil && !errors.Is(err, repositories.ErrProjectNotFound) {
+			return nil, err
+		}
+
+		if err != nil {
+			task.Project = ""
+			detached = true
+		}
+	}
+
+	if detached {
+		task.UpdatedAt = time.Now()
+		if err := s.taskRepository.Update(task); err != nil {
+			return nil, err
+		}
+	}
+
+	ids, err := s.subtree(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := refreshDependents(s.taskRepository, ids); err != nil {
+		return nil, err
+	}
+
+	// A task blocked by its own subtasks is refreshed along with them.
+	return s.taskRepository.Read(id)
+}
+
+func (s *taskService) PurgeTrash(retention time.Duration) (int64, error) {
+	ids, err := s.taskRepository.Purge(time.Now().Add(-retention))
+	if err != nil {
+		return 0, err
+	}
+
+	if err := s.deleteRelated(ids); err != nil {
+		return 0, err
+	}
+	return int64(len(ids)), nil
+}
+
+// Operations are checked up front, so an invalid batch changes nothing.
+func (s *taskService) validateBatch(owner string, operations []*TaskOperation) error {
+	size := 0
+	for _, operation := range operations {
+		size += len(operation.IDs)
+
+		switch operation.Op {
+		case TaskOperationComplete, TaskOperationUncomplete, TaskOperationDelete:
+		case TaskOperationMove:
+			if err := s.setProject(&entities.Task{Owner: owner}, &TaskInput{Project: operation.Project}); err != nil {
+				return err
+			}
+		case TaskOperationAddTag:
+			tags, err := normalizeTags([]string{operation.Tag})
+			if err != nil {
+				return err
+			}
+			operation.Tag = tags[0]
+		default:
+			return errors.New("operation must be one of complete, uncomplete, delete, move or addTag")
+		}
+	}
+
+	if size == 0 {
+		return errors.New("no tasks specified")
+	}
+
+	if size > MaxBatchTasks {
+		return fmt.Errorf("batch can't contain more than %d tasks", MaxBatchTasks)
+	}
+	return nil
+}
+
+func (s *taskService) applyOperation(owner string, operation *TaskOperation, ids []string, workflow *entities.Workflow, now time.Time) error {
+	change := &repositories.TaskChange{At: now}
+
+	switch operation.Op {
+	case TaskOperationComplete:
+		completed := true
+		change.Completed = &completed
+		change.Status = workflow.Final().Key
+	case TaskOperationUncomplete:
+		completed := false
+		change.Completed = &completed
+		change.Status = workflow.Initial().Key
+	case TaskOperationDelete:
+		return s.taskRepository.TrashMany(owner, ids, now)
+	case TaskOperationMove:
+		change.Project = &operation.Project
+	case TaskOperationAddTag:
+		change.AddTag = operation.Tag
+	}
+
+	return s.taskRepository.UpdateMany(owner, ids, change)
+}
+
+func (s *taskService) Batch(owner string, operations []*TaskOperation) ([]*TaskOperationResult, error) {
+	if err := s.validateBatch(owner, operations); err != nil {
+		return nil, err
+	}
+
+	workflow, err := s.workflow(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*TaskOperationResult
+	now := time.Now()
+
+	for _, operation := range operations {
+		tasks, err := s.taskRepository.ReadMany(owner, operation.IDs)
+		if err != nil {
+			return nil, err
+		}
+
+		found := map[string]*entities.Task{}
+		for _, task := range tasks {
+			found[task.ID] = task
+		}
+
+		var ids []string
+		failed := map[string]error{}
+
+		for _, id := range operation.IDs {
+			task, ok := found[id]
+			switch {
+			case !ok:
+				failed[id] = repositories.ErrTaskNotFound
+			case (operation.Op == TaskOperationComplete || operation.Op == TaskOperationUncomplete) &&
+				completionStatus(workflow, task, operation.Op == TaskOperationComplete) == nil:
+				failed[id] = ErrTaskTransition
+			case operation.Op == TaskOperationComplete && !task.Completed && task.Recurrence != "":
+				// Recurring tasks move to their next occurrence one by one.
+				setCompleted(task, true, now)
+				task.UpdatedAt = now
+
+				history, err := s.recur(task, workflow, now)
+				if err == nil {
+					err = s.taskRepository.Update(task)
+				}
+				if err == nil && history != nil {
+					err = s.taskRepository.Create(history)
+				}
+
+				// A task whose series has ended stays completed and its
+				// dependents are refreshed as Update does.
+				if err == nil && task.Completed {
+					err = refreshDependents(s.taskRepository, []string{task.ID})
+				}
+				failed[id] = err
+			case operation.Op == TaskOperationAddTag && len(task.Tags) >= MaxTaskTags && !hasTag(task, operation.Tag):
+				failed[id] = ErrTaskTags
+			default:
+				ids = append(ids, id)
+			}
+		}
+
+		if len(ids) > 0 {
+			changed := ids
+			if operation.Op == TaskOperationDelete {
+				changed = nil
+				for _, id := range ids {
+					subtree, err := s.subtree(id)
+					if err != nil {
+						return nil, err
+					}
+					changed = append(changed, subtree...)
+				}
+			}
+
+			if err := s.applyOperation(owner, operation, ids, workflow, now); err != nil {
+				return nil, err
+			}
+
+			switch operation.Op {
+			case TaskOperationComplete, TaskOperationUncomplete, TaskOperationDelete:
+				if err := refreshDependents(s.taskRepository, changed); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		for _, id := range operation.IDs {
+			results = append(results, &TaskOperationResult{ID: id, Op: operation.Op, Err: failed[id]})
+		}
+	}
+
+	return results, nil
+}
+
+func hasTag(task *entities.Task, tag string) bool {
+	for _, t := range task.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
 }
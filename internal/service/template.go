@@ -0,0 +1,374 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/turbekoff/todo/internal/domain/entities"
+	"github.com/turbekoff/todo/internal/domain/repositories"
+)
+
+const (
+	MaxTemplateNameLength = 100
+	MaxTemplateTasks      = 100
+	MaxTemplateDueOffset  = 3650
+)
+
+const (
+	MaxTemplateValues      = 20
+	MaxTemplateValueLength = 100
+)
+
+var placeholderExpression = regexp.MustCompile(`\{\{\s*([A-Za-z][0-9A-Za-z_]*)\s*\}\}`)
+
+var placeholderKeyExpression = regexp.MustCompile(`^[A-Za-z][0-9A-Za-z_]{0,31}$`)
+
+// Built-in placeholders can't be overridden by values.
+var builtinPlaceholders = map[string]func(time.Time) string{
+	"date":  func(date time.Time) string { return date.Format(dueDateLayout) },
+	"year":  func(date time.Time) string { return date.Format("2006") },
+	"month": func(date time.Time) string { return date.Format("01") },
+	"day":   func(date time.Time) string { return date.Format("02") },
+}
+
+var ErrTemplateTasks = fmt.Errorf("template must have 1-%d tasks", MaxTemplateTasks)
+
+type templateService struct {
+	taskService        TaskService
+	userRepository     repositories.UserRepository
+	templateRepository repositories.TemplateRepository
+}
+
+func NewTemplateService(
+	taskService TaskService,
+	userRepository repositories.UserRepository,
+	templateRepository repositories.TemplateRepository,
+) TemplateService {
+	return &templateService{
+		taskService:        taskService,
+		userRepository:     userRepository,
+		templateRepository: templateRepository,
+	}
+}
+
+func templateTasks(inputs []*TemplateTaskInput, level int, count *int) ([]*entities.TemplateTask, error) {
+	if len(inputs) > 0 && level > MaxTaskDepth {
+		return nil, ErrTaskDepth
+	}
+
+	var tasks []*entities.TemplateTask
+	for _, input := range inputs {
+		if *count++; *count > MaxTemplateTasks {
+			return nil, ErrTemplateTasks
+		}
+
+		name := strings.TrimSpace(input.Name)
+		if name == "" {
+			return nil, errors.New("empty task name specified")
+		}
+
+		if utf8.RuneCountInString(input.Notes) > MaxTaskNotesLength {
+			return nil, ErrTaskNotesLength
+		}
+
+		priority, err := entities.ParsePriority(input.Priority)
+		if err != nil {
+			return nil, err
+		}
+
+		// Tags are checked with their placeholders filled in, the values
+		// are checked again once they are known.
+		var sample []string
+		for _, tag := range input.Tags {
+			sample = append(sample, placeholderExpression.ReplaceAllString(tag, "x"))
+		}
+		if _, err := normalizeTags(sample); err != nil {
+			return nil, err
+		}
+
+		if input.DueOffset != nil && (*input.DueOffset < -MaxTemplateDueOffset || *input.DueOffset > MaxTemplateDueOffset) {
+			return nil, fmt.Errorf("due offset must be between %d and %d days", -MaxTemplateDueOffset, MaxTemplateDueOffset)
+		}
+
+		if input.DueTime != "" {
+			if input.DueOffset == nil {
+				return nil, errors.New("due time specified without due offset")
+			}
+
+			if _, err := time.Parse(dueTimeLayout, input.DueTime); err != nil {
+				return nil, errors.New("due time must be formatted as HH:MM")
+			}
+		}
+
+		subtasks, err := templateTasks(input.Subtasks, level+1, count)
+		if err != nil {
+			return nil, err
+		}
+
+		var tags []string
+		for _, tag := range input.Tags {
+			tags = append(tags, strings.TrimSpace(tag))
+		}
+
+		tasks = append(tasks, &entities.TemplateTask{
+			Name:      name,
+			Notes:     input.Notes,
+			Priority:  priority,
+			Tags:      tags,
+			DueOffset: input.DueOffset,
+			DueTime:   input.DueTime,
+			Subtasks:  subtasks,
+		})
+	}
+	return tasks, nil
+}
+
+func (s *templateService) validate(input *TemplateInput) ([]*entities.TemplateTask, error) {
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" {
+		return nil, errors.New("empty name specified")
+	}
+
+	if utf8.RuneCountInString(input.Name) > MaxTemplateNameLength {
+		return nil, errors.New("template name is too long")
+	}
+
+	if len(input.Tasks) == 0 {
+		return nil, ErrTemplateTasks
+	}
+
+	var count int
+	return templateTasks(input.Tasks, 1, &count)
+}
+
+func (s *templateService) Create(owner string, input *TemplateInput) (*entities.Template, error) {
+	tasks, err := s.validate(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.userRepository.Read(owner); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	template := &entities.Template{
+		Owner:     owner,
+		Name:      input.Name,
+		Tasks:     tasks,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := s.templateRepository.Create(template); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+func (s *templateService) Read(id string) (*entities.Template, error) {
+	return s.templateRepository.Read(id)
+}
+
+func (s *templateService) ReadAllByOwner(owner string) ([]*entities.Template, error) {
+	return s.templateRepository.ReadAllByOwner(owner)
+}
+
+func (s *templateService) Update(id string, input *TemplateInput) (*entities.Template, error) {
+	tasks, err := s.validate(input)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := s.templateRepository.Read(id)
+	if err != nil {
+		return nil, err
+	}
+
+	template.Name = input.Name
+	template.Tasks = tasks
+	template.UpdatedAt = time.Now()
+
+	if err := s.templateRepository.Update(template); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+func (s *templateService) Delete(id string) error {
+	return s.templateRepository.Delete(id)
+}
+
+func resolvePlaceholders(text string, values map[string]string) (string, error) {
+	var err error
+	result := placeholderExpression.ReplaceAllStringFunc(text, func(match string) string {
+		key := placeholderExpression.FindStringSubmatch(match)[1]
+		value, ok := values[key]
+		if !ok && err == nil {
+			err = fmt.Errorf("unknown placeholder {{%s}}", key)
+		}
+		return value
+	})
+	return result, err
+}
+
+func placeholderValues(date time.Time, input map[string]string) (map[string]string, error) {
+	if len(input) > MaxTemplateValues {
+		return nil, fmt.Errorf("template can't be instantiated with more than %d values", MaxTemplateValues)
+	}
+
+	values := map[string]string{}
+	for key, value := range input {
+		if !placeholderKeyExpression.MatchString(key) {
+			return nil, errors.New("placeholder must consist of 1-32 latin letters, digits and underscores starting with a letter")
+		}
+
+		if _, ok := builtinPlaceholders[key]; ok {
+			return nil, fmt.Errorf("placeholder {{%s}} is built in", key)
+		}
+
+		if utf8.RuneCountInString(value) > MaxTemplateValueLength {
+			return nil, fmt.Errorf("value of {{%s}} can't be longer than %d characters", key, MaxTemplateValueLength)
+		}
+		values[key] = value
+	}
+
+	for key, format := range builtinPlaceholders {
+		values[key] = format(date)
+	}
+	return values, nil
+}
+
+type templateInstance struct {
+	input    *TaskInput
+	subtasks []*templateInstance
+}
+
+// Every task is resolved before any is created, so a bad value fails the
+// instantiation as a whole.
+func resolveTasks(tasks []*entities.TemplateTask, date time.Time, values map[string]string, input *TemplateInstanceInput) ([]*templateInstance, error) {
+	var instances []*templateInstance
+	for _, task := range tasks {
+		name, err := resolvePlaceholders(task.Name, values)
+		if err != nil {
+			return nil, err
+		}
+
+		if strings.TrimSpace(name) == "" {
+			return nil, errors.New("task name is empty once placeholders are resolved")
+		}
+
+		notes, err := resolvePlaceholders(task.Notes, values)
+		if err != nil {
+			return nil, err
+		}
+
+		if utf8.RuneCountInString(notes) > MaxTaskNotesLength {
+			return nil, ErrTaskNotesLength
+		}
+
+		var tags []string
+		for _, tag := range task.Tags {
+			tag, err := resolvePlaceholders(tag, values)
+			if err != nil {
+				return nil, err
+			}
+			tags = append(tags, tag)
+		}
+
+		if _, err := normalizeTags(tags); err != nil {
+			return nil, err
+		}
+
+		taskInput := &TaskInput{
+			Project:  input.Project,
+			Name:     name,
+			Notes:    notes,
+			Priority: task.Priority.String(),
+			Tags:     tags,
+		}
+
+		if task.DueOffset != nil {
+			taskInput.DueDate = date.AddDate(0, 0, *task.DueOffset).Format(dueDateLayout)
+			taskInput.DueTime = task.DueTime
+			taskInput.TimeZone = input.TimeZone
+		}
+
+		subtasks, err := resolveTasks(task.Subtasks, date, values, input)
+		if err != nil {
+			return nil, err
+		}
+
+		instances = append(instances, &templateInstance{input: taskInput, subtasks: subtasks})
+	}
+	return instances, nil
+}
+
+func (s *templateService) createTasks(owner, parent string, instances []*templateInstance, created *[]*entities.Task) error {
+	for _, instance := range instances {
+		instance.input.Parent = parent
+
+		task, err := s.taskService.Create(owner, instance.input)
+		if err != nil {
+			return err
+		}
+		*created = append(*created, task)
+
+		if err := s.createTasks(owner, task.ID, instance.subtasks, created); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *templateService) Instantiate(id string, input *TemplateInstanceInput) ([]*entities.Task, error) {
+	template, err := s.templateRepository.Read(id)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := location(input.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+
+	date := time.Now().In(loc)
+	if input.Date != "" {
+		if date, err = time.ParseInLocation(dueDateLayout, input.Date, loc); err != nil {
+			return nil, errors.New("date must be formatted as YYYY-MM-DD")
+		}
+	}
+
+	values, err := placeholderValues(date, input.Values)
+	if err != nil {
+		return nil, err
+	}
+
+	instances, err := resolveTasks(template.Tasks, date, values, input)
+	if err != nil {
+		return nil, err
+	}
+
+	var created []*entities.Task
+	if err := s.createTasks(template.Owner, "", instances, &created); err != nil {
+		// Purging the top level tasks removes their subtasks as well.
+		for _, task := range created {
+			if task.Parent != "" {
+				continue
+			}
+
+			if purgeErr := s.taskService.Purge(task.ID); purgeErr != nil {
+				err = errors.Join(err, fmt.Errorf("failed to roll back task %s: %w", task.ID, purgeErr))
+			}
+		}
+		return nil, err
+	}
+
+	return created, nil
+}
@@ -0,0 +1,320 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"github.com/turbekoff/todo/internal/domain/entities"
+	"github.com/turbekoff/todo/internal/domain/repositories"
+)
+
+const MaxTimeEntryNoteLength = 500
+
+const MaxTimeReportDays = 366
+
+var (
+	ErrTimerNotRunning     = errors.New("timer of the task isn't running")
+	ErrTimeEntryNoteLength = fmt.Errorf("note can't be longer than %d characters", MaxTimeEntryNoteLength)
+)
+
+type timeService struct {
+	taskRepository      repositories.TaskRepository
+	timeEntryRepository repositories.TimeEntryRepository
+}
+
+func NewTimeService(
+	taskRepository repositories.TaskRepository,
+	timeEntryRepository repositories.TimeEntryRepository,
+) TimeService {
+	return &timeService{
+		taskRepository:      taskRepository,
+		timeEntryRepository: timeEntryRepository,
+	}
+}
+
+func (s *timeService) task(id string) (*entities.Task, error) {
+	task, err := s.taskRepository.Read(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if task.DeletedAt != nil {
+		return nil, ErrTaskTrashed
+	}
+	return task, nil
+}
+
+func validateTimeEntry(input *TimeEntryInput, now time.Time) error {
+	if utf8.RuneCountInString(input.Note) > MaxTimeEntryNoteLength {
+		return ErrTimeEntryNoteLength
+	}
+
+	if input.Start.IsZero() {
+		return errors.New("empty start specified")
+	}
+
+	if input.Start.After(now) {
+		return errors.New("start can't be in the future")
+	}
+
+	if input.End != nil {
+		if !input.End.After(input.Start) {
+			return errors.New("end must be after start")
+		}
+
+		if input.End.After(now) {
+			return errors.New("end can't be in the future")
+		}
+	}
+	return nil
+}
+
+func (s *timeService) Start(task, note string) (*entities.TimeEntry, error) {
+	if utf8.RuneCountInString(note) > MaxTimeEntryNoteLength {
+		return nil, ErrTimeEntryNoteLength
+	}
+
+	t, err := s.task(task)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	entry := &entities.TimeEntry{
+		Owner:     t.Owner,
+		Task:      t.ID,
+		Start:     now,
+		Note:      note,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := s.timeEntryRepository.Create(entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (s *timeService) Stop(task string) (*entities.TimeEntry, error) {
+	t, err := s.taskRepository.Read(task)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := s.timeEntryRepository.ReadRunning(t.Owner)
+	if errors.Is(err, repositories.ErrTimeEntryNotFound) {
+		return nil, ErrTimerNotRunning
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if entry.Task != t.ID {
+		return nil, ErrTimerNotRunning
+	}
+
+	now := time.Now()
+	entry.End = &now
+	entry.UpdatedAt = now
+
+	if err := s.timeEntryRepository.Update(entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (s *timeService) Create(task string, input *TimeEntryInput) (*entities.TimeEntry, error) {
+	now := time.Now()
+	if err := validateTimeEntry(input, now); err != nil {
+		return nil, err
+	}
+
+	if input.End == nil {
+		return nil, errors.New("empty end specified")
+	}
+
+	t, err := s.task(task)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &entities.TimeEntry{
+		Owner:     t.Owner,
+		Task:      t.ID,
+		Start:     input.Start,
+		End:       input.End,
+		Note:      input.Note,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := s.timeEntryRepository.Create(entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (s *timeService) Read(id string) (*entities.TimeEntry, error) {
+	return s.timeEntryRepository.Read(id)
+}
+
+func (s *timeService) ReadAllByTask(task string) ([]*entities.TimeEntry, error) {
+	return s.timeEntryRepository.ReadAllByTask(task)
+}
+
+func (s *timeService) Update(id string, input *TimeEntryInput) (*entities.TimeEntry, error) {
+	now := time.Now()
+	if err := validateTimeEntry(input, now); err != nil {
+		return nil, err
+	}
+
+	entry, err := s.timeEntryRepository.Read(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.End == nil && entry.End != nil {
+		return nil, errors.New("empty end specified")
+	}
+
+	entry.Start = input.Start
+	entry.End = input.End
+	entry.Note = input.Note
+	entry.UpdatedAt = now
+
+	if err := s.timeEntryRepository.Update(entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (s *timeService) Delete(id string) error {
+	return s.timeEntryRepository.Delete(id)
+}
+
+func timeTotals(durations map[string]time.Duration) []*TimeTotal {
+	totals := []*TimeTotal{}
+	for key, duration := range durations {
+		totals = append(totals, &TimeTotal{Key: key, Duration: duration})
+	}
+
+	sort.Slice(totals, func(i, j int) bool {
+		if totals[i].Duration != totals[j].Duration {
+			return totals[i].Duration > totals[j].Duration
+		}
+		return totals[i].Key < totals[j].Key
+	})
+	return totals
+}
+
+func (s *timeService) Report(owner string, query *TimeReportQuery) (*TimeReport, error) {
+	loc, err := location(query.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+
+	from, err := time.ParseInLocation(dueDateLayout, query.From, loc)
+	if err != nil {
+		return nil, errors.New("from must be formatted as YYYY-MM-DD")
+	}
+
+	to, err := time.ParseInLocation(dueDateLayout, query.To, loc)
+	if err != nil {
+		return nil, errors.New("to must be formatted as YYYY-MM-DD")
+	}
+
+	to = to.AddDate(0, 0, 1)
+	if !to.After(from) {
+		return nil, errors.New("to can't be before from")
+	}
+
+	if to.After(from.AddDate(0, 0, MaxTimeReportDays)) {
+		return nil, fmt.Errorf("report can't span more than %d days", MaxTimeReportDays)
+	}
+
+	entries, err := s.timeEntryRepository.ReadAllByPeriod(owner, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, entry := range entries {
+		ids = append(ids, entry.Task)
+	}
+
+	tasks := map[string]*entities.Task{}
+	if len(ids) > 0 {
+		found, err := s.taskRepository.ReadMany(owner, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, task := range found {
+			tasks[task.ID] = task
+		}
+	}
+
+	report := &TimeReport{From: query.From, To: query.To, TimeZone: loc.String(), Days: []*TimeTotal{}}
+	days, tags, projects := map[string]time.Duration{}, map[string]time.Duration{}, map[string]time.Duration{}
+	now := time.Now()
+
+	for _, entry := range entries {
+		task, ok := tasks[entry.Task]
+		if !ok {
+			continue
+		}
+
+		start, end := entry.Start, now
+		if entry.End != nil {
+			end = *entry.End
+		}
+
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if !end.After(start) {
+			continue
+		}
+
+		duration := end.Sub(start)
+		report.Total += duration
+
+		for _, tag := range task.Tags {
+			tags[tag] += duration
+		}
+
+		project := task.Project
+		if project == "" {
+			project = ProjectInbox
+		}
+		projects[project] += duration
+
+		// Entries spanning midnight are split between the days.
+		for start.Before(end) {
+			day := start.In(loc)
+			next := time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, loc)
+			if next.After(end) {
+				next = end
+			}
+
+			days[day.Format(dueDateLayout)] += next.Sub(start)
+			start = next
+		}
+	}
+
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		if duration, ok := days[day.Format(dueDateLayout)]; ok {
+			report.Days = append(report.Days, &TimeTotal{Key: day.Format(dueDateLayout), Duration: duration})
+		}
+	}
+
+	report.Tags = timeTotals(tags)
+	report.Projects = timeTotals(projects)
+	return report, nil
+}
@@ -2,9 +2,11 @@ package service
 
 import (
 	"errors"
+	"fmt"
 	"regexp"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/turbekoff/todo/internal/domain/entities"
 	"github.com/turbekoff/todo/internal/domain/repositories"
@@ -13,11 +15,24 @@ import (
 
 var userNameExpression = regexp.MustCompile(`^[0-9A-Za-z]{8,30}$`)
 
+var statusKeyExpression = regexp.MustCompile(`^[A-Za-z][0-9A-Za-z_-]{0,31}$`)
+
+const (
+	MaxWorkflowStatuses         = 10
+	MaxWorkflowStatusNameLength = 50
+)
+
 type userService struct {
 	hasher            hash.Hasher
 	userRepository    repositories.UserRepository
 	taskRepository    repositories.TaskRepository
 	sessionRepository repositories.SessionRepository
+	projectRepository repositories.ProjectRepository
+
+	timeEntryRepository repositories.TimeEntryRepository
+	templateRepository  repositories.TemplateRepository
+	filterRepository    repositories.FilterRepository
+	commentRepository   repositories.CommentRepository
 }
 
 func NewUserService(
@@ -25,24 +40,41 @@ func NewUserService(
 	userRepository repositories.UserRepository,
 	taskRepository repositories.TaskRepository,
 	sessionRepository repositories.SessionRepository,
+	projectRepository repositories.ProjectRepository,
+	timeEntryRepository repositories.TimeEntryRepository,
+	templateRepository repositories.TemplateRepository,
+	filterRepository repositories.FilterRepository,
+	commentRepository repositories.CommentRepository,
 ) UserService {
 	return &userService{
 		hasher:            hasher,
 		userRepository:    userRepository,
 		taskRepository:    taskRepository,
 		sessionRepository: sessionRepository,
+		projectRepository: projectRepository,
+
+		timeEntryRepository: timeEntryRepository,
+		templateRepository:  templateRepository,
+		filterRepository:    filterRepository,
+		commentRepository:   commentRepository,
 	}
 }
 
-func (s *userService) validate(name, password string) error {
+func (s *userService) validateName(id, name string) error {
 	if !userNameExpression.MatchString(name) {
 		return errors.New("name must consist of 8-30 latin letters and digits")
 	}
 
-	if _, err := s.userRepository.ReadByName(name); !errors.Is(err, repositories.ErrUserNotFound) {
-		return errors.New("user with specified name already exists")
+	if user, err := s.userRepository.ReadByName(name); !errors.Is(err, repositories.ErrUserNotFound) {
+		if err != nil || user.ID != id {
+			return errors.New("user with specified name already exists")
+		}
 	}
 
+	return nil
+}
+
+func (s *userService) validatePassword(password string) error {
 	if len(password) < 8 {
 		return errors.New("password must be at least 8 characters")
 	}
@@ -85,7 +117,11 @@ func (s *userService) validate(name, password string) error {
 }
 
 func (s *userService) Create(name, password string) error {
-	if err := s.validate(name, password); err != nil {
+	if err := s.validateName("", name); err != nil {
+		return err
+	}
+
+	if err := s.validatePassword(password); err != nil {
 		return err
 	}
 
@@ -109,8 +145,8 @@ func (s *userService) Read(id string) (*entities.User, error) {
 	return s.userRepository.Read(id)
 }
 
-func (s *userService) Update(id, name, password string) (*entities.User, error) {
-	if err := s.validate(name, password); err != nil {
+func (s *userService) Update(id string, input *UserInput) (*entities.User, error) {
+	if err := s.validateName(id, input.Name); err != nil {
 		return nil, err
 	}
 
@@ -119,8 +155,17 @@ func (s *userService) Update(id, name, password string) (*entities.User, error)
 		return nil, err
 	}
 
-	user.Name = name
-	user.Password = password
+	if input.Password != nil {
+		if err := s.validatePassword(*input.Password); err != nil {
+			return nil, err
+		}
+
+		if user.Password, err = s.hasher.Hash(*input.Password); err != nil {
+			return nil, err
+		}
+	}
+
+	user.Name = input.Name
 	user.UpdatedAt = time.Now()
 
 	if err := s.userRepository.Update(user); err != nil {
@@ -130,6 +175,99 @@ func (s *userService) Update(id, name, password string) (*entities.User, error)
 	return user, nil
 }
 
+func (s *userService) SetAutoArchive(id string, days int) (*entities.User, error) {
+	if days < 0 || days > MaxArchiveDays {
+		return nil, fmt.Errorf("days must be between 0 and %d", MaxArchiveDays)
+	}
+
+	user, err := s.userRepository.Read(id)
+	if err != nil {
+		return nil, err
+	}
+
+	user.AutoArchiveDays = days
+	user.UpdatedAt = time.Now()
+
+	if err := s.userRepository.Update(user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (s *userService) ReadWorkflow(id string) (*entities.Workflow, error) {
+	user, err := s.userRepository.Read(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Workflow == nil {
+		return entities.DefaultWorkflow(), nil
+	}
+	return user.Workflow, nil
+}
+
+func validateWorkflow(workflow *entities.Workflow) error {
+	if len(workflow.Statuses) > MaxWorkflowStatuses {
+		return fmt.Errorf("workflow can't have more than %d statuses", MaxWorkflowStatuses)
+	}
+
+	keys := map[string]bool{}
+	for _, status := range workflow.Statuses {
+		if !statusKeyExpression.MatchString(status.Key) {
+			return errors.New("status key must consist of 1-32 latin letters, digits, dashes and underscores, starting with a letter")
+		}
+
+		if keys[status.Key] {
+			return errors.New("duplicate status key " + status.Key)
+		}
+		keys[status.Key] = true
+
+		status.Name = strings.TrimSpace(status.Name)
+		if status.Name == "" || utf8.RuneCountInString(status.Name) > MaxWorkflowStatusNameLength {
+			return fmt.Errorf("status name must consist of 1-%d characters", MaxWorkflowStatusNameLength)
+		}
+	}
+
+	for _, status := range workflow.Statuses {
+		for _, next := range status.Next {
+			if !keys[next] {
+				return errors.New("unknown status " + next + " in the transitions of " + status.Key)
+			}
+		}
+	}
+
+	if workflow.Initial() == nil || workflow.Final() == nil {
+		return errors.New("workflow must have both a done status and a status that isn't")
+	}
+	return nil
+}
+
+func (s *userService) SetWorkflow(id string, workflow *entities.Workflow) (*entities.Workflow, error) {
+	if workflow != nil {
+		if err := validateWorkflow(workflow); err != nil {
+			return nil, err
+		}
+	}
+
+	user, err := s.userRepository.Read(id)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Workflow = workflow
+	user.UpdatedAt = time.Now()
+
+	if err := s.userRepository.Update(user); err != nil {
+		return nil, err
+	}
+
+	if workflow == nil {
+		return entities.DefaultWorkflow(), nil
+	}
+	return workflow, nil
+}
+
 func (s *userService) Delete(id string) error {
 	err := s.userRepository.Delete(id)
 	if err != nil {
@@ -138,7 +276,18 @@ func (s *userService) Delete(id string) error {
 
 	tasks, _ := s.taskRepository.ReadAllByOwner(id)
 	for _, task := range tasks {
-		s.taskRepository.Delete(task.ID)
+		ids, _ := s.taskRepository.Delete(task.ID)
+		s.commentRepository.DeleteAllByTask(ids)
+	}
+
+	s.timeEntryRepository.DeleteAllByOwner(id)
+	s.templateRepository.DeleteAllByOwner(id)
+	s.filterRepository.DeleteAllByOwner(id)
+	s.commentRepository.DeleteAllByAuthor(id)
+
+	projects, _ := s.projectRepository.ReadAllByOwner(id)
+	for _, project := range projects {
+		s.projectRepository.Delete(project.ID)
 	}
 
 	sessions, _ := s.sessionRepository.ReadAllByOwner(id)
@@ -0,0 +1,238 @@
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type Op string
+
+const (
+	And  Op = "AND"
+	Or   Op = "OR"
+	Not  Op = "NOT"
+	Term Op = "TERM"
+)
+
+var ErrSyntax = errors.New("invalid filter expression")
+
+// maxDepth keeps deeply nested expressions from exhausting the stack.
+const maxDepth = 32
+
+// Key is empty for a Term that is a phrase of words.
+type Node struct {
+	Op       Op
+	Children []*Node
+	Key      string
+	Value    string
+}
+
+type token struct {
+	text   string
+	quoted bool
+	pos    int
+}
+
+// Parse reads an expression like `due this week AND tag:work AND NOT completed`.
+// AND binds tighter than OR and adjacent terms are combined by AND.
+func Parse(s string) (*Node, error) {
+	tokens, err := tokenize(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("%w: empty expression", ErrSyntax)
+	}
+
+	p := &parser{tokens: tokens, end: utf8.RuneCountInString(s)}
+	node, err := p.or(0)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos < len(p.tokens) {
+		return nil, p.unexpected()
+	}
+	return node, nil
+}
+
+func syntaxError(pos int, format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s at character %d", ErrSyntax, fmt.Sprintf(format, args...), pos+1)
+}
+
+// Quotes keep spaces and parentheses inside a word, a backslash escapes a quote.
+func tokenize(s string) ([]token, error) {
+	var tokens []token
+	runes := []rune(s)
+
+	for i := 0; i < len(runes); {
+		switch r := runes[i]; {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')':
+			tokens = append(tokens, token{text: string(r), pos: i})
+			i++
+		default:
+			var word strings.Builder
+			start, quote := i, 0
+			quoted, inQuotes := false, false
+
+			for ; i < len(runes); i++ {
+				r := runes[i]
+				if !inQuotes && (unicode.IsSpace(r) || r == '(' || r == ')') {
+					break
+				}
+
+				switch {
+				case r == '"':
+					inQuotes, quoted, quote = !inQuotes, true, i
+				case r == '\\' && inQuotes && i+1 < len(runes) && runes[i+1] == '"':
+					word.WriteRune('"')
+					i++
+				default:
+					word.WriteRune(r)
+				}
+			}
+
+			if inQuotes {
+				return nil, syntaxError(quote, "unterminated quote")
+			}
+			tokens = append(tokens, token{text: word.String(), quoted: quoted, pos: start})
+		}
+	}
+	return tokens, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	end    int
+}
+
+func (p *parser) at() int {
+	if p.pos >= len(p.tokens) {
+		return p.end
+	}
+	return p.tokens[p.pos].pos
+}
+
+func (p *parser) unexpected() error {
+	if p.pos >= len(p.tokens) {
+		return syntaxError(p.end, "unexpected end")
+	}
+	return syntaxError(p.at(), "unexpected %q", p.tokens[p.pos].text)
+}
+
+// Quoted tokens are never keywords.
+func (p *parser) keyword(text string) bool {
+	if p.pos >= len(p.tokens) || p.tokens[p.pos].quoted {
+		return false
+	}
+	return strings.EqualFold(p.tokens[p.pos].text, text)
+}
+
+func (p *parser) or(depth int) (*Node, error) {
+	node, err := p.and(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	for p.keyword(string(Or)) {
+		p.pos++
+		right, err := p.and(depth)
+		if err != nil {
+			return nil, err
+		}
+		node = combine(Or, node, right)
+	}
+	return node, nil
+}
+
+func (p *parser) and(depth int) (*Node, error) {
+	node, err := p.not(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	for p.pos < len(p.tokens) && !p.keyword(string(Or)) && !p.keyword(")") {
+		if p.keyword(string(And)) {
+			p.pos++
+		}
+
+		right, err := p.not(depth)
+		if err != nil {
+			return nil, err
+		}
+		node = combine(And, node, right)
+	}
+	return node, nil
+}
+
+func (p *parser) not(depth int) (*Node, error) {
+	if depth > maxDepth {
+		return nil, syntaxError(p.at(), "nested deeper than %d levels", maxDepth)
+	}
+
+	if p.keyword(string(Not)) {
+		p.pos++
+		node, err := p.not(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return &Node{Op: Not, Children: []*Node{node}}, nil
+	}
+
+	if p.keyword("(") {
+		p.pos++
+		node, err := p.or(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		if !p.keyword(")") {
+			return nil, syntaxError(p.at(), "missing )")
+		}
+		p.pos++
+		return node, nil
+	}
+
+	return p.term()
+}
+
+func (p *parser) term() (*Node, error) {
+	if p.pos >= len(p.tokens) || p.operator() {
+		return nil, p.unexpected()
+	}
+
+	if key, value, ok := strings.Cut(p.tokens[p.pos].text, ":"); ok && key != "" {
+		p.pos++
+		return &Node{Op: Term, Key: key, Value: value}, nil
+	}
+
+	var words []string
+	for p.pos < len(p.tokens) && !p.operator() && !strings.Contains(p.tokens[p.pos].text, ":") {
+		words = append(words, p.tokens[p.pos].text)
+		p.pos++
+	}
+
+	if len(words) == 0 {
+		return nil, p.unexpected()
+	}
+	return &Node{Op: Term, Value: strings.Join(words, " ")}, nil
+}
+
+func (p *parser) operator() bool {
+	return p.keyword(string(And)) || p.keyword(string(Or)) || p.keyword(string(Not)) || p.keyword("(") || p.keyword(")")
+}
+
+func combine(op Op, left, right *Node) *Node {
+	if left.Op == op {
+		left.Children = append(left.Children, right)
+		return left
+	}
+	return &Node{Op: op, Children: []*Node{left, right}}
+}
@@ -0,0 +1,110 @@
+package expr
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// format writes the node in prefix notation, terms as key:value or as a
+// quoted phrase.
+func format(node *Node) string {
+	if node.Op == Term {
+		if node.Key != "" {
+			return node.Key + ":" + node.Value
+		}
+		return `"` + node.Value + `"`
+	}
+
+	parts := []string{string(node.Op)}
+	for _, child := range node.Children {
+		parts = append(parts, format(child))
+	}
+	return "(" + strings.Join(parts, " ") + ")"
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		want string
+	}{
+		{"key and value", "tag:work", "tag:work"},
+		{"phrase", "due this week", `"due this week"`},
+		{"empty value", "project:", "project:"},
+		{"value with colon", "due:<=2024-05-01", "due:<=2024-05-01"},
+		{"and", "tag:work AND completed", `(AND tag:work "completed")`},
+		{"adjacent terms", "tag:work completed", `(AND tag:work "completed")`},
+		{"phrase before term", "due today tag:work", `(AND "due today" tag:work)`},
+		{"operators in any case", "tag:a or not tag:b", "(OR tag:a (NOT tag:b))"},
+		{"chain flattened", "tag:a AND tag:b tag:c", "(AND tag:a tag:b tag:c)"},
+
+		{"and before or", "tag:a OR tag:b AND tag:c", "(OR tag:a (AND tag:b tag:c))"},
+		{"and before or on the left", "tag:a AND tag:b OR tag:c", "(OR (AND tag:a tag:b) tag:c)"},
+		{"not before and", "NOT tag:a AND tag:b", "(AND (NOT tag:a) tag:b)"},
+		{"not before or", "NOT tag:a OR tag:b", "(OR (NOT tag:a) tag:b)"},
+		{"double negation", "NOT NOT completed", `(NOT (NOT "completed"))`},
+
+		{"parentheses", "(tag:a OR tag:b) AND tag:c", "(AND (OR tag:a tag:b) tag:c)"},
+		{"negated group", "NOT (tag:a OR tag:b)", "(NOT (OR tag:a tag:b))"},
+		{"nested groups", "((tag:a))", "tag:a"},
+		{"parentheses without spaces", "(tag:a)(tag:b)", "(AND tag:a tag:b)"},
+		{"group ends a phrase", "(due today) tag:a", `(AND "due today" tag:a)`},
+
+		{"quoted value", `name:"weekly report"`, "name:weekly report"},
+		{"quoted parentheses", `name:"a (b)"`, "name:a (b)"},
+		{"escaped quote", `name:"say \"hi\""`, `name:say "hi"`},
+		{"quoted operator", `name:"OR"`, "name:OR"},
+		{"quoted operator as phrase", `"and"`, `"and"`},
+		{"quoted part of a word", `name:re"port"s`, "name:reports"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q) error: %v", tt.expr, err)
+			}
+
+			if got := format(node); got != tt.want {
+				t.Fatalf("Parse(%q) = %s, want %s", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		want string
+	}{
+		{"empty", "", "empty expression"},
+		{"blank", "   ", "empty expression"},
+		{"leading operator", "AND tag:a", `unexpected "AND" at character 1`},
+		{"trailing operator", "tag:a AND", "unexpected end at character 10"},
+		{"repeated operator", "tag:a OR OR tag:b", `unexpected "OR" at character 10`},
+		{"trailing not", "tag:a NOT", "unexpected end at character 10"},
+		{"missing )", "(tag:a OR tag:b", "missing ) at character 16"},
+		{"unmatched )", "tag:a )", `unexpected ")" at character 7`},
+		{"empty group", "()", `unexpected ")" at character 2`},
+		{"empty key", ":work", `unexpected ":work" at character 1`},
+		{"unterminated quote", `tag:a name:"report`, "unterminated quote at character 12"},
+		{"position in characters", `name:"ä" )`, `unexpected ")" at character 10`},
+		{"too deep", strings.Repeat("(", 40) + "tag:a" + strings.Repeat(")", 40), "nested deeper than 32 levels at character 34"},
+		{"too many negations", strings.Repeat("NOT ", 40), "nested deeper than 32 levels at character 133"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.expr)
+			if !errors.Is(err, ErrSyntax) {
+				t.Fatalf("Parse(%q) error = %v, want ErrSyntax", tt.expr, err)
+			}
+
+			if want := ErrSyntax.Error() + ": " + tt.want; err.Error() != want {
+				t.Fatalf("Parse(%q) error = %q, want %q", tt.expr, err.Error(), want)
+			}
+		})
+	}
+}
@@ -1,8 +1,8 @@
 package jwt
 
 import (
+	"crypto/rand"
 	"fmt"
-	"math/rand"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -16,10 +16,18 @@ func NewManager(signingKey string) *Manager {
 	return &Manager{signingKey: signingKey}
 }
 
-func (m *Manager) Generate(id string, ttl time.Duration) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS512, jwt.RegisteredClaims{
-		Subject:   id,
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+type claims struct {
+	Session string `json:"sid"`
+	jwt.RegisteredClaims
+}
+
+func (m *Manager) Generate(id, session string, ttl time.Duration) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS512, claims{
+		Session: session,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   id,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+		},
 	})
 
 	return token.SignedString([]byte(m.signingKey))
@@ -27,18 +35,15 @@ func (m *Manager) Generate(id string, ttl time.Duration) (string, error) {
 
 func (m *Manager) GenerateRefresh() (string, error) {
 	b := make([]byte, 32)
-	s := rand.NewSource(time.Now().Unix())
-	r := rand.New(s)
-
-	if _, err := r.Read(b); err != nil {
+	if _, err := rand.Read(b); err != nil {
 		return "", err
 	}
 
 	return fmt.Sprintf("%x", b), nil
 }
 
-func (m *Manager) Parse(accessToken string) (string, error) {
-	token, err := jwt.Parse(accessToken, func(token *jwt.Token) (interface{}, error) {
+func (m *Manager) Parse(accessToken string) (string, string, error) {
+	token, err := jwt.ParseWithClaims(accessToken, &claims{}, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
@@ -46,12 +51,12 @@ func (m *Manager) Parse(accessToken string) (string, error) {
 	})
 
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return "", fmt.Errorf("token claims not found")
+	claims, ok := token.Claims.(*claims)
+	if !ok || claims.Subject == "" || claims.Session == "" {
+		return "", "", fmt.Errorf("token claims not found")
 	}
-	return claims["sub"].(string), nil
+	return claims.Subject, claims.Session, nil
 }
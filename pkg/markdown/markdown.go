@@ -0,0 +1,23 @@
+package markdown
+
+import (
+	"bytes"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+var (
+	converter = goldmark.New(goldmark.WithExtensions(extension.GFM))
+	policy    = bluemonday.UGCPolicy()
+)
+
+// Raw HTML of the source is escaped and the result is sanitized.
+func ToHTML(source string) (string, error) {
+	var buf bytes.Buffer
+	if err := converter.Convert([]byte(source), &buf); err != nil {
+		return "", err
+	}
+	return policy.Sanitize(buf.String()), nil
+}
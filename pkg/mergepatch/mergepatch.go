@@ -0,0 +1,61 @@
+package mergepatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+var ErrInvalid = errors.New("invalid merge patch")
+
+// Apply follows RFC 7396: null removes a member and other values replace it.
+func Apply(target, patch []byte) ([]byte, error) {
+	targetValue, err := decode(target)
+	if err != nil {
+		return nil, err
+	}
+
+	patchValue, err := decode(patch)
+	if err != nil {
+		return nil, ErrInvalid
+	}
+
+	return json.Marshal(merge(targetValue, patchValue))
+}
+
+// decode keeps numbers as written, so large integers survive the round trip.
+func decode(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	if decoder.More() {
+		return nil, ErrInvalid
+	}
+	return value, nil
+}
+
+func merge(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = merge(targetObject[key], value)
+	}
+	return targetObject
+}
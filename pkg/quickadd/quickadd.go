@@ -0,0 +1,440 @@
+package quickadd
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+var ErrEmptyName = errors.New("task name is empty")
+
+const (
+	dateLayout = "2006-01-02"
+	timeLayout = "15:04"
+)
+
+// Project is the name of a project as written in the text.
+type Task struct {
+	Name       string
+	Project    string
+	DueDate    string
+	DueTime    string
+	Tags       []string
+	Priority   string
+	Recurrence string
+}
+
+var priorities = []string{"none", "low", "medium", "high", "urgent"}
+
+// weekdays are recognized by their full names only, short ones like "sun"
+// or "sat" are common words.
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+var weekdayCodes = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+var months = map[string]time.Month{
+	"january": time.January, "jan": time.January,
+	"february": time.February, "feb": time.February,
+	"march": time.March, "mar": time.March,
+	"april": time.April, "apr": time.April,
+	"may":  time.May,
+	"june": time.June, "jun": time.June,
+	"july": time.July, "jul": time.July,
+	"august": time.August, "aug": time.August,
+	"september": time.September, "sep": time.September, "sept": time.September,
+	"october": time.October, "oct": time.October,
+	"november": time.November, "nov": time.November,
+	"december": time.December, "dec": time.December,
+}
+
+var units = map[string]string{
+	"day": "DAILY", "days": "DAILY",
+	"week": "WEEKLY", "weeks": "WEEKLY",
+	"month": "MONTHLY", "months": "MONTHLY",
+	"year": "YEARLY", "years": "YEARLY",
+}
+
+var frequencies = map[string]string{
+	"daily":   "DAILY",
+	"weekly":  "WEEKLY",
+	"monthly": "MONTHLY",
+	"yearly":  "YEARLY",
+}
+
+var (
+	isoDateExpression = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+	dayExpression     = regexp.MustCompile(`^(\d{1,2})(?:st|nd|rd|th)?$`)
+	clockExpression   = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm)?$`)
+)
+
+type parser struct {
+	words []string
+	now   time.Time
+	today time.Time
+
+	date     *time.Time
+	clock    *time.Duration
+	tags     []string
+	priority string
+	project  string
+
+	frequency string
+	interval  int
+	byDay     []time.Weekday
+}
+
+// Parse reads a task like "Pay rent tomorrow 9am #home !high every month":
+// #tags, !priority, +project, dates, times and recurrences. The other words make
+// up the name. A time without a date is due today, or tomorrow once it has
+// passed.
+func Parse(text string, now time.Time, loc *time.Location) (*Task, error) {
+	now = now.In(loc)
+	p := &parser{
+		words: strings.Fields(text),
+		now:   now,
+		today: time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc),
+	}
+
+	var name []string
+	for i := 0; i < len(p.words); {
+		if n := p.match(i); n > 0 {
+			i += n
+			continue
+		}
+		name = append(name, p.words[i])
+		i++
+	}
+
+	task := &Task{
+		Name:     strings.Join(name, " "),
+		Project:  p.project,
+		Tags:     p.tags,
+		Priority: p.priority,
+	}
+	if task.Name == "" {
+		return nil, ErrEmptyName
+	}
+
+	if p.frequency != "" {
+		task.Recurrence = p.recurrence()
+	}
+
+	if p.date == nil && (p.clock != nil || p.frequency != "") {
+		p.date = p.firstDate()
+	}
+
+	if p.date != nil {
+		task.DueDate = p.date.Format(dateLayout)
+	}
+
+	if p.clock != nil {
+		task.DueTime = p.today.Add(*p.clock).Format(timeLayout)
+	}
+	return task, nil
+}
+
+func (p *parser) word(i int) string {
+	if i >= len(p.words) {
+		return ""
+	}
+	return strings.TrimRight(strings.ToLower(p.words[i]), ",.;")
+}
+
+func (p *parser) match(i int) int {
+	word := p.word(i)
+
+	if strings.HasPrefix(word, "#") && len(word) > 1 {
+		p.tags = append(p.tags, strings.TrimRight(p.words[i][1:], ",.;"))
+		return 1
+	}
+
+	if strings.HasPrefix(word, "+") && p.project == "" {
+		if r, _ := utf8.DecodeRuneInString(word[1:]); unicode.IsLetter(r) {
+			p.project = strings.TrimRight(p.words[i][1:], ",.;")
+			return 1
+		}
+	}
+
+	if strings.HasPrefix(word, "!") && p.priority == "" && indexOf(priorities, word[1:]) >= 0 {
+		p.priority = word[1:]
+		return 1
+	}
+
+	if p.frequency == "" {
+		if n := p.matchRecurrence(i); n > 0 {
+			return n
+		}
+	}
+
+	if p.date == nil {
+		if n := p.matchDate(i); n > 0 {
+			return n
+		}
+	}
+
+	if p.clock == nil {
+		if n := p.matchClock(i); n > 0 {
+			return n
+		}
+	}
+	return 0
+}
+
+func (p *parser) matchRecurrence(i int) int {
+	if frequency, ok := frequencies[p.word(i)]; ok {
+		p.frequency, p.interval = frequency, 1
+		return 1
+	}
+
+	if p.word(i) != "every" {
+		return 0
+	}
+
+	next := p.word(i + 1)
+	if frequency, ok := units[next]; ok && !strings.HasSuffix(next, "s") {
+		p.frequency, p.interval = frequency, 1
+		return 2
+	}
+
+	if next == "weekday" || next == "weekdays" {
+		p.frequency, p.interval = "WEEKLY", 1
+		p.byDay = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+		return 2
+	}
+
+	if weekday, ok := weekdays[next]; ok {
+		p.frequency, p.interval = "WEEKLY", 1
+		p.byDay = []time.Weekday{weekday}
+		return 2
+	}
+
+	if frequency, ok := units[p.word(i+2)]; ok {
+		if next == "other" && !strings.HasSuffix(p.word(i+2), "s") {
+			p.frequency, p.interval = frequency, 2
+			return 3
+		}
+
+		if interval, err := strconv.Atoi(next); err == nil && interval > 1 && interval <= 999 {
+			p.frequency, p.interval = frequency, interval
+			return 3
+		}
+	}
+	return 0
+}
+
+func (p *parser) matchDate(i int) int {
+	word := p.word(i)
+
+	switch word {
+	case "today":
+		p.setDate(p.today)
+		return 1
+	case "tomorrow":
+		p.setDate(p.today.AddDate(0, 0, 1))
+		return 1
+	case "in":
+		count, err := strconv.Atoi(p.word(i + 1))
+		if p.word(i+1) == "a" || p.word(i+1) == "an" {
+			count, err = 1, nil
+		}
+		if err != nil || count < 1 || count > 999 {
+			return 0
+		}
+
+		switch units[p.word(i+2)] {
+		case "DAILY":
+			p.setDate(p.today.AddDate(0, 0, count))
+		case "WEEKLY":
+			p.setDate(p.today.AddDate(0, 0, 7*count))
+		case "MONTHLY":
+			p.setDate(p.today.AddDate(0, count, 0))
+		case "YEARLY":
+			p.setDate(p.today.AddDate(count, 0, 0))
+		default:
+			return 0
+		}
+		return 3
+	}
+
+	// "on" and "next" only prefix a date.
+	if word == "on" || word == "next" {
+		if _, ok := weekdays[p.word(i+1)]; ok || word == "on" {
+			if n := p.matchDate(i + 1); n > 0 {
+				return n + 1
+			}
+		}
+		return 0
+	}
+
+	if weekday, ok := weekdays[word]; ok {
+		days := (int(weekday)-int(p.today.Weekday())+6)%7 + 1
+		p.setDate(p.today.AddDate(0, 0, days))
+		return 1
+	}
+
+	if isoDateExpression.MatchString(word) {
+		date, err := time.ParseInLocation(dateLayout, word, p.today.Location())
+		if err != nil {
+			return 0
+		}
+		p.setDate(date)
+		return 1
+	}
+
+	if month, ok := months[word]; ok {
+		if day, ok := p.day(i + 1); ok {
+			return p.setMonthDay(month, day, 2)
+		}
+	}
+
+	if day, ok := p.day(i); ok {
+		if month, ok := months[p.word(i+1)]; ok {
+			return p.setMonthDay(month, day, 2)
+		}
+	}
+	return 0
+}
+
+func (p *parser) day(i int) (int, bool) {
+	match := dayExpression.FindStringSubmatch(p.word(i))
+	if match == nil {
+		return 0, false
+	}
+
+	day, _ := strconv.Atoi(match[1])
+	return day, day >= 1 && day <= 31
+}
+
+func (p *parser) setMonthDay(month time.Month, day int, n int) int {
+	for year := p.today.Year(); year <= p.today.Year()+4; year++ {
+		date := time.Date(year, month, day, 0, 0, 0, 0, p.today.Location())
+		if date.Day() == day && !date.Before(p.today) {
+			p.setDate(date)
+			return n
+		}
+	}
+	return 0
+}
+
+func (p *parser) setDate(date time.Time) {
+	p.date = &date
+}
+
+func (p *parser) matchClock(i int) int {
+	word := p.word(i)
+
+	if word == "at" {
+		if n := p.clockAt(i+1, true); n > 0 {
+			return n + 1
+		}
+		return 0
+	}
+	return p.clockAt(i, false)
+}
+
+// A bare hour like 9 needs a preceding "at".
+func (p *parser) clockAt(i int, bare bool) int {
+	word := p.word(i)
+	if word == "noon" {
+		return p.setClock(12, 0, 1)
+	}
+
+	match := clockExpression.FindStringSubmatch(word)
+	if match == nil {
+		return 0
+	}
+
+	hour, _ := strconv.Atoi(match[1])
+	minute, _ := strconv.Atoi(match[2])
+	meridiem, n := match[3], 1
+
+	// A meridiem may follow as a separate word, as in "9 am".
+	if meridiem == "" && (p.word(i+1) == "am" || p.word(i+1) == "pm") {
+		meridiem, n = p.word(i+1), 2
+	}
+
+	switch {
+	case meridiem != "":
+		if hour < 1 || hour > 12 {
+			return 0
+		}
+		hour %= 12
+		if meridiem == "pm" {
+			hour += 12
+		}
+	case match[2] == "" && !bare:
+		return 0
+	}
+
+	if hour > 23 || minute > 59 {
+		return 0
+	}
+	return p.setClock(hour, minute, n)
+}
+
+func (p *parser) setClock(hour, minute int, n int) int {
+	clock := time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute
+	p.clock = &clock
+	return n
+}
+
+func (p *parser) firstDate() *time.Time {
+	date := p.today
+	if p.clock != nil && !p.today.Add(*p.clock).After(p.now) {
+		date = date.AddDate(0, 0, 1)
+	}
+
+	if len(p.byDay) > 0 {
+		for indexOfWeekday(p.byDay, date.Weekday()) < 0 {
+			date = date.AddDate(0, 0, 1)
+		}
+	}
+	return &date
+}
+
+func (p *parser) recurrence() string {
+	rule := "FREQ=" + p.frequency
+	if p.interval > 1 {
+		rule += fmt.Sprintf(";INTERVAL=%d", p.interval)
+	}
+
+	if len(p.byDay) > 0 {
+		var days []string
+		for _, day := range p.byDay {
+			days = append(days, weekdayCodes[day])
+		}
+		rule += ";BYDAY=" + strings.Join(days, ",")
+	}
+	return rule
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
+
+func indexOfWeekday(values []time.Weekday, value time.Weekday) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
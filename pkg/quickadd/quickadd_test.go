@@ -0,0 +1,172 @@
+package quickadd
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+func TestParse(t *testing.T) {
+	berlin := mustLoad(t, "Europe/Berlin")
+	// Wednesday morning.
+	now := time.Date(2024, time.May, 1, 10, 0, 0, 0, berlin)
+
+	tests := []struct {
+		name string
+		text string
+		want Task
+	}{
+		{"headline example", "Pay rent tomorrow 9am #home !high every month", Task{
+			Name: "Pay rent", DueDate: "2024-05-02", DueTime: "09:00", Tags: []string{"home"}, Priority: "high", Recurrence: "FREQ=MONTHLY",
+		}},
+		{"name only", "Buy milk", Task{Name: "Buy milk"}},
+		{"extra spaces", "  Buy   milk  ", Task{Name: "Buy milk"}},
+
+		{"today", "Buy milk today", Task{Name: "Buy milk", DueDate: "2024-05-01"}},
+		{"tomorrow", "Buy milk tomorrow", Task{Name: "Buy milk", DueDate: "2024-05-02"}},
+		{"any case", "Call Mom TOMORROW", Task{Name: "Call Mom", DueDate: "2024-05-02"}},
+		{"date with punctuation", "Call mom tomorrow, please", Task{Name: "Call mom please", DueDate: "2024-05-02"}},
+		{"weekday", "Call mom friday", Task{Name: "Call mom", DueDate: "2024-05-03"}},
+		{"weekday of today", "Call mom wednesday", Task{Name: "Call mom", DueDate: "2024-05-08"}},
+		{"next weekday", "Call mom next friday", Task{Name: "Call mom", DueDate: "2024-05-03"}},
+		{"on weekday", "Call mom on monday", Task{Name: "Call mom", DueDate: "2024-05-06"}},
+		{"in days", "Renew passport in 3 days", Task{Name: "Renew passport", DueDate: "2024-05-04"}},
+		{"in a day", "Renew passport in a day", Task{Name: "Renew passport", DueDate: "2024-05-02"}},
+		{"in weeks", "Renew passport in 2 weeks", Task{Name: "Renew passport", DueDate: "2024-05-15"}},
+		{"in a month", "Renew passport in a month", Task{Name: "Renew passport", DueDate: "2024-06-01"}},
+		{"in a year", "Renew passport in 1 year", Task{Name: "Renew passport", DueDate: "2025-05-01"}},
+		{"iso date", "Dentist 2024-06-15", Task{Name: "Dentist", DueDate: "2024-06-15"}},
+		{"month and day", "Dentist may 20", Task{Name: "Dentist", DueDate: "2024-05-20"}},
+		{"day and month", "Dentist 3rd june", Task{Name: "Dentist", DueDate: "2024-06-03"}},
+		{"passed month day", "Prank april 1", Task{Name: "Prank", DueDate: "2025-04-01"}},
+		{"leap day", "Party feb 29", Task{Name: "Party", DueDate: "2028-02-29"}},
+
+		{"morning time passed", "Standup 9:30am", Task{Name: "Standup", DueDate: "2024-05-02", DueTime: "09:30"}},
+		{"afternoon time", "Standup 3pm", Task{Name: "Standup", DueDate: "2024-05-01", DueTime: "15:00"}},
+		{"separate meridiem", "Call 9 pm", Task{Name: "Call", DueDate: "2024-05-01", DueTime: "21:00"}},
+		{"midnight", "Call 12am", Task{Name: "Call", DueDate: "2024-05-02", DueTime: "00:00"}},
+		{"noon", "Lunch noon", Task{Name: "Lunch", DueDate: "2024-05-01", DueTime: "12:00"}},
+		{"24 hour time", "Standup 21:00", Task{Name: "Standup", DueDate: "2024-05-01", DueTime: "21:00"}},
+		{"at hour", "Standup at 11", Task{Name: "Standup", DueDate: "2024-05-01", DueTime: "11:00"}},
+		{"at current time", "Standup at 10", Task{Name: "Standup", DueDate: "2024-05-02", DueTime: "10:00"}},
+		{"date and time", "Dentist friday at 14:30", Task{Name: "Dentist", DueDate: "2024-05-03", DueTime: "14:30"}},
+		{"time before date", "Dentist 8am next monday", Task{Name: "Dentist", DueDate: "2024-05-06", DueTime: "08:00"}},
+
+		{"tags", "Report #work #q2", Task{Name: "Report", Tags: []string{"work", "q2"}}},
+		{"tag with punctuation", "Report #work, now", Task{Name: "Report now", Tags: []string{"work"}}},
+		{"tag keeps case", "Report #Work", Task{Name: "Report", Tags: []string{"Work"}}},
+		{"priority", "Fix bug !urgent", Task{Name: "Fix bug", Priority: "urgent"}},
+		{"priority in any case", "Fix bug !LOW", Task{Name: "Fix bug", Priority: "low"}},
+		{"project", "Write spec +Work", Task{Name: "Write spec", Project: "Work"}},
+		{"project with underscores", "Write spec +home_office.", Task{Name: "Write spec", Project: "home_office"}},
+		{"all tokens", "+work !medium Review #code", Task{Name: "Review", Project: "work", Tags: []string{"code"}, Priority: "medium"}},
+
+		{"recurrence", "Water plants daily", Task{Name: "Water plants", DueDate: "2024-05-01", Recurrence: "FREQ=DAILY"}},
+		{"every unit", "Water plants every week", Task{Name: "Water plants", DueDate: "2024-05-01", Recurrence: "FREQ=WEEKLY"}},
+		{"every other unit", "Water plants every other week", Task{Name: "Water plants", DueDate: "2024-05-01", Recurrence: "FREQ=WEEKLY;INTERVAL=2"}},
+		{"every number of units", "Pay bills every 3 months", Task{Name: "Pay bills", DueDate: "2024-05-01", Recurrence: "FREQ=MONTHLY;INTERVAL=3"}},
+		{"every weekday name", "Gym every monday", Task{Name: "Gym", DueDate: "2024-05-06", Recurrence: "FREQ=WEEKLY;BYDAY=MO"}},
+		{"every weekday", "Gym every weekday", Task{Name: "Gym", DueDate: "2024-05-01", Recurrence: "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR"}},
+		{"every weekday after the time", "Gym every weekday 7am", Task{Name: "Gym", DueDate: "2024-05-02", DueTime: "07:00", Recurrence: "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR"}},
+		{"recurrence with date", "Pay rent every month 2024-06-01", Task{Name: "Pay rent", DueDate: "2024-06-01", Recurrence: "FREQ=MONTHLY"}},
+
+		{"bare hour", "Call 9", Task{Name: "Call 9"}},
+		{"hour out of range", "Meeting 25:00", Task{Name: "Meeting 25:00"}},
+		{"hour out of meridiem range", "Meeting 13pm", Task{Name: "Meeting 13pm"}},
+		{"minutes out of range", "Meeting 10:75", Task{Name: "Meeting 10:75"}},
+		{"invalid iso date", "Report 2024-02-30", Task{Name: "Report 2024-02-30"}},
+		{"missing month day", "Report feb 30", Task{Name: "Report feb 30"}},
+		{"month without day", "May report", Task{Name: "May report"}},
+		{"in without count", "Read in the morning", Task{Name: "Read in the morning"}},
+		{"in without unit", "Read in 3 parts", Task{Name: "Read in 3 parts"}},
+		{"every without unit", "Every time counts", Task{Name: "Every time counts"}},
+		{"every plural unit", "Every days counts", Task{Name: "Every days counts"}},
+		{"every single unit", "Stretch every 1 days", Task{Name: "Stretch every 1 days"}},
+		{"next without weekday", "Next steps", Task{Name: "Next steps"}},
+		{"on without date", "Work on docs", Task{Name: "Work on docs"}},
+		{"unknown priority", "Fix bug !asap", Task{Name: "Fix bug !asap"}},
+		{"bare hash", "Issue # 12", Task{Name: "Issue # 12"}},
+		{"project starting with a digit", "Call +49 30 123", Task{Name: "Call +49 30 123"}},
+		{"second date", "Read book tomorrow today", Task{Name: "Read book today", DueDate: "2024-05-02"}},
+		{"second time", "Call 9am 5pm", Task{Name: "Call 5pm", DueDate: "2024-05-02", DueTime: "09:00"}},
+		{"second priority", "Fix bug !high !low", Task{Name: "Fix bug !low", Priority: "high"}},
+		{"second project", "Plan +home +work", Task{Name: "Plan +work", Project: "home"}},
+		{"second recurrence", "Gym daily weekly", Task{Name: "Gym weekly", DueDate: "2024-05-01", Recurrence: "FREQ=DAILY"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.text, now, berlin)
+			if err != nil {
+				t.Fatalf("Parse(%q) error: %v", tt.text, err)
+			}
+
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Fatalf("Parse(%q) = %+v, want %+v", tt.text, *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseEmptyName(t *testing.T) {
+	now := time.Date(2024, time.May, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []string{
+		"",
+		"   ",
+		"tomorrow",
+		"tomorrow 9am #home !high every month",
+		"+work #home",
+		"in 3 days",
+	}
+
+	for _, text := range tests {
+		t.Run(text, func(t *testing.T) {
+			if _, err := Parse(text, now, time.UTC); !errors.Is(err, ErrEmptyName) {
+				t.Fatalf("Parse(%q) error = %v, want ErrEmptyName", text, err)
+			}
+		})
+	}
+}
+
+func TestParseTimeZone(t *testing.T) {
+	// Thursday in Berlin, Wednesday night in UTC and evening in New York.
+	now := time.Date(2024, time.May, 1, 23, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		loc  string
+		text string
+		want Task
+	}{
+		{"UTC", "Call today", Task{Name: "Call", DueDate: "2024-05-01"}},
+		{"Europe/Berlin", "Call today", Task{Name: "Call", DueDate: "2024-05-02"}},
+		{"Europe/Berlin", "Call friday", Task{Name: "Call", DueDate: "2024-05-03"}},
+		{"Europe/Berlin", "Call 9am", Task{Name: "Call", DueDate: "2024-05-02", DueTime: "09:00"}},
+		{"America/New_York", "Call 8pm", Task{Name: "Call", DueDate: "2024-05-01", DueTime: "20:00"}},
+		{"America/New_York", "Call 7pm", Task{Name: "Call", DueDate: "2024-05-02", DueTime: "19:00"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.loc+" "+tt.text, func(t *testing.T) {
+			got, err := Parse(tt.text, now, mustLoad(t, tt.loc))
+			if err != nil {
+				t.Fatalf("Parse(%q) error: %v", tt.text, err)
+			}
+
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Fatalf("Parse(%q) = %+v, want %+v", tt.text, *got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package rank
+
+import (
+	"errors"
+	"strings"
+)
+
+// Ranks are strings of base 36 digits compared lexicographically. A rank
+// never ends with the lowest digit, so there is always room before it.
+const alphabet = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+const base = len(alphabet)
+
+// width is the length ranks appended with After are padded to, so a long
+// list grows by incrementing its last digits instead of adding new ones.
+const width = 6
+
+var (
+	ErrInvalid = errors.New("invalid rank")
+	ErrOrder   = errors.New("ranks are out of order")
+)
+
+func Valid(s string) bool {
+	if s == "" || s[len(s)-1] == alphabet[0] {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(alphabet, s[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func digit(s string, i, fallback int) int {
+	if i >= len(s) {
+		return fallback
+	}
+	return strings.IndexByte(alphabet, s[i])
+}
+
+// An empty a stands for the start of the list, an empty b for its end.
+func Between(a, b string) (string, error) {
+	if (a != "" && !Valid(a)) || (b != "" && !Valid(b)) {
+		return "", ErrInvalid
+	}
+
+	if b != "" && a >= b {
+		return "", ErrOrder
+	}
+
+	var result []byte
+	for i := 0; ; i++ {
+		low := digit(a, i, 0)
+		high := base
+		if b != "" {
+			high = digit(b, i, 0)
+		}
+
+		if high-low > 1 {
+			return string(append(result, alphabet[(low+high)/2])), nil
+		}
+
+		// With a digit left between the bounds the rest of b no longer
+		// matters, as the result is already below it.
+		result = append(result, alphabet[low])
+		if high-low == 1 {
+			b = ""
+		}
+	}
+}
+
+func After(a string) (string, error) {
+	if a == "" {
+		return Between("", "")
+	}
+
+	if !Valid(a) {
+		return "", ErrInvalid
+	}
+
+	digits := []byte(a)
+	for len(digits) < width {
+		digits = append(digits, alphabet[0])
+	}
+
+	for i := len(digits) - 1; i >= 0; i-- {
+		if d := digit(string(digits), i, 0); d < base-1 {
+			digits[i] = alphabet[d+1]
+			return strings.TrimRight(string(digits[:i+1]), alphabet[:1]), nil
+		}
+	}
+
+	// Every digit is the highest one.
+	return Between(a, "")
+}
@@ -0,0 +1,121 @@
+package rank
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want string
+	}{
+		{"empty list", "", "", "i"},
+		{"start of the list", "", "i", "9"},
+		{"end of the list", "a", "", "n"},
+		{"after the highest digit", "z", "", "zi"},
+		{"before the lowest rank", "", "1", "0i"},
+		{"before a rank with leading zero", "", "01", "00i"},
+		{"digit left between", "a", "c", "b"},
+		{"adjacent digits", "a", "b", "ai"},
+		{"adjacent last digits", "a1", "a2", "a1i"},
+		{"a is a prefix of b", "a", "ab", "a5"},
+		{"a is a prefix of adjacent b", "a", "a1", "a0i"},
+		{"b is shorter", "ai", "b", "ar"},
+		{"longer a", "a1zz", "a2", "a1zzi"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Between(tt.a, tt.b)
+			if err != nil {
+				t.Fatalf("Between(%q, %q) error: %v", tt.a, tt.b, err)
+			}
+
+			if got != tt.want {
+				t.Fatalf("Between(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+			}
+
+			if !Valid(got) || got <= tt.a || (tt.b != "" && got >= tt.b) {
+				t.Fatalf("Between(%q, %q) = %q is out of order", tt.a, tt.b, got)
+			}
+		})
+	}
+}
+
+func TestBetweenInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want error
+	}{
+		{"trailing lowest digit", "a0", "", ErrInvalid},
+		{"upper case", "", "A", ErrInvalid},
+		{"not a digit", "a-", "b", ErrInvalid},
+		{"equal", "a", "a", ErrOrder},
+		{"reversed", "b", "a", ErrOrder},
+		{"reversed prefix", "ab", "a", ErrOrder},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Between(tt.a, tt.b); !errors.Is(err, tt.want) {
+				t.Fatalf("Between(%q, %q) error = %v, want %v", tt.a, tt.b, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAfter(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		want string
+	}{
+		{"empty list", "", "i"},
+		{"short rank", "i", "i00001"},
+		{"padded rank", "i00001", "i00002"},
+		{"carry", "i0000z", "i0001"},
+		{"long rank", "i000001", "i000002"},
+		{"highest digits", "zzzzzz", "zzzzzzi"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := After(tt.a)
+			if err != nil {
+				t.Fatalf("After(%q) error: %v", tt.a, err)
+			}
+
+			if got != tt.want {
+				t.Fatalf("After(%q) = %q, want %q", tt.a, got, tt.want)
+			}
+
+			if !Valid(got) || got <= tt.a {
+				t.Fatalf("After(%q) = %q is out of order", tt.a, got)
+			}
+		})
+	}
+
+	if _, err := After("a0"); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("After(%q) error = %v, want ErrInvalid", "a0", err)
+	}
+}
+
+func TestAfterKeepsOrder(t *testing.T) {
+	rank := ""
+	for i := 0; i < 2000; i++ {
+		next, err := After(rank)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if next <= rank {
+			t.Fatalf("After(%q) = %q is out of order", rank, next)
+		}
+		rank = next
+	}
+}
@@ -0,0 +1,371 @@
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+var ErrInvalid = errors.New("invalid recurrence rule")
+
+var weekdays = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// maxPeriods bounds the search for the next occurrence, a yearly rule for
+// the 29th of February needs up to 8 years to find one.
+const maxPeriods = 1000
+
+// maxGap is the most periods between two occurrences, a counted series is
+// searched for as many periods per occurrence on top of maxPeriods.
+const maxGap = 8
+
+// Rule is a subset of the RFC 5545 recurrence rule. It supports FREQ,
+// INTERVAL, BYDAY for weekly rules, BYMONTHDAY for monthly rules, COUNT and
+// UNTIL as a date. Weeks start on Monday.
+type Rule struct {
+	Frequency  Frequency
+	Interval   int
+	ByDay      []time.Weekday
+	ByMonthDay []int
+	Count      int
+	Until      string
+}
+
+// An optional "RRULE:" prefix is ignored.
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(s)), "RRULE:")
+	if s == "" {
+		return nil, fmt.Errorf("%w: empty rule", ErrInvalid)
+	}
+
+	rule := &Rule{Interval: 1}
+	seen := map[string]bool{}
+
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("%w: malformed part %q", ErrInvalid, part)
+		}
+
+		if seen[key] {
+			return nil, fmt.Errorf("%w: duplicated %s", ErrInvalid, key)
+		}
+		seen[key] = true
+
+		switch key {
+		case "FREQ":
+			switch Frequency(value) {
+			case Daily, Weekly, Monthly, Yearly:
+				rule.Frequency = Frequency(value)
+			default:
+				return nil, fmt.Errorf("%w: unsupported frequency %s", ErrInvalid, value)
+			}
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 || interval > 999 {
+				return nil, fmt.Errorf("%w: interval must be between 1 and 999", ErrInvalid)
+			}
+			rule.Interval = interval
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				weekday := indexOf(weekdays, day)
+				if weekday < 0 {
+					return nil, fmt.Errorf("%w: unknown weekday %s", ErrInvalid, day)
+				}
+				rule.ByDay = append(rule.ByDay, time.Weekday(weekday))
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(value, ",") {
+				n, err := strconv.Atoi(day)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return nil, fmt.Errorf("%w: month day must be between 1 and 31 or -31 and -1", ErrInvalid)
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, n)
+			}
+		case "COUNT":
+			count, err := strconv.Atoi(value)
+			if err != nil || count < 1 || count > 999 {
+				return nil, fmt.Errorf("%w: count must be between 1 and 999", ErrInvalid)
+			}
+			rule.Count = count
+		case "UNTIL":
+			if _, err := time.Parse("20060102", value); err != nil {
+				return nil, fmt.Errorf("%w: until must be a date formatted as YYYYMMDD", ErrInvalid)
+			}
+			rule.Until = value
+		default:
+			return nil, fmt.Errorf("%w: unsupported part %s", ErrInvalid, key)
+		}
+	}
+
+	if rule.Frequency == "" {
+		return nil, fmt.Errorf("%w: frequency not specified", ErrInvalid)
+	}
+
+	if rule.Count > 0 && rule.Until != "" {
+		return nil, fmt.Errorf("%w: COUNT and UNTIL can't be combined", ErrInvalid)
+	}
+
+	if len(rule.ByDay) > 0 && rule.Frequency != Weekly {
+		return nil, fmt.Errorf("%w: BYDAY is only supported by weekly rules", ErrInvalid)
+	}
+
+	if len(rule.ByMonthDay) > 0 && rule.Frequency != Monthly {
+		return nil, fmt.Errorf("%w: BYMONTHDAY is only supported by monthly rules", ErrInvalid)
+	}
+
+	return rule, nil
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Frequency)}
+
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+
+	if len(r.ByDay) > 0 {
+		var days []string
+		for _, day := range r.sortedDays() {
+			days = append(days, weekdays[day])
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+
+	if len(r.ByMonthDay) > 0 {
+		var days []string
+		for _, day := range r.ByMonthDay {
+			days = append(days, strconv.Itoa(day))
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+
+	if r.Until != "" {
+		parts = append(parts, "UNTIL="+r.Until)
+	}
+
+	return strings.Join(parts, ";")
+}
+
+func (r *Rule) sortedDays() []time.Weekday {
+	days := append([]time.Weekday(nil), r.ByDay...)
+	sort.Slice(days, func(i, j int) bool {
+		return (days[i]+6)%7 < (days[j]+6)%7
+	})
+	return days
+}
+
+// Occurrences keep the wall clock of start in its location, so they don't
+// drift across daylight saving time changes. Days missing in a month, like the
+// 31st of April, are skipped as RFC 5545 requires. Start is the first of the
+// COUNT occurrences of a counted series.
+func (r *Rule) Next(start, after time.Time) (time.Time, bool) {
+	if after.Before(start) {
+		after = start.Add(-time.Nanosecond)
+	}
+
+	var until time.Time
+	if r.Until != "" {
+		date, _ := time.Parse("20060102", r.Until)
+		until = time.Date(date.Year(), date.Month(), date.Day()+1, 0, 0, 0, 0, start.Location())
+	}
+
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	first, periods := r.skip(start, after)/interval*interval, maxPeriods
+	if r.Count > 0 {
+		if start.After(after) {
+			return start, true
+		}
+
+		// Occurrences of a counted series are numbered, so the series is
+		// walked from its start.
+		first, periods = 0, maxPeriods+maxGap*r.Count
+	}
+
+	seen := 1
+	for period := first; period < first+periods*interval; period += interval {
+		for _, occurrence := range r.occurrences(start, period) {
+			if !until.IsZero() && !occurrence.Before(until) {
+				return time.Time{}, false
+			}
+
+			if occurrence.Before(start) || r.Count > 0 && occurrence.Equal(start) {
+				continue
+			}
+
+			if r.Count > 0 {
+				if seen++; seen > r.Count {
+					return time.Time{}, false
+				}
+			}
+
+			if occurrence.After(after) {
+				return occurrence, true
+			}
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// Rest lowers the count by the occurrences before next, so the series keeps its
+// number of occurrences.
+func (r *Rule) Rest(start, next time.Time) *Rule {
+	rest := *r
+	if r.Count == 0 {
+		return &rest
+	}
+
+	uncounted := *r
+	uncounted.Count = 0
+
+	used := 1
+	for t, ok := uncounted.Next(start, start); ok && t.Before(next); t, ok = uncounted.Next(start, t) {
+		used++
+	}
+
+	rest.Count -= used
+	return &rest
+}
+
+func (r *Rule) skip(start, after time.Time) int {
+	after = after.In(start.Location())
+
+	var periods int
+	switch r.Frequency {
+	case Daily:
+		periods = days(start, after)
+	case Weekly:
+		periods = days(start, after) / 7
+	case Monthly:
+		periods = (after.Year()-start.Year())*12 + int(after.Month()-start.Month())
+	case Yearly:
+		periods = after.Year() - start.Year()
+	}
+
+	if periods--; periods < 0 {
+		return 0
+	}
+	return periods
+}
+
+func days(a, b time.Time) int {
+	da := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	db := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(db.Sub(da).Hours() / 24)
+}
+
+// As RFC 5545 requires, a wall clock skipped by a daylight saving time change
+// is read with the offset before the change and a repeated one refers to its
+// first occurrence.
+func at(start time.Time, year int, month time.Month, day int) (time.Time, bool) {
+	hour, min, sec := start.Clock()
+	t := time.Date(year, month, day, hour, min, sec, 0, start.Location())
+	if t.Day() != day || t.Month() != month {
+		return t, false
+	}
+
+	_, offset := t.Zone()
+	if h, m, s := t.Clock(); h != hour || m != min || s != sec {
+		_, before := t.Add(-24 * time.Hour).Zone()
+		wall := time.Date(year, month, day, hour, min, sec, 0, time.UTC)
+		return wall.Add(-time.Duration(before) * time.Second).In(start.Location()), true
+	}
+
+	if _, before := t.Add(-24 * time.Hour).Zone(); before > offset {
+		if first := t.Add(-time.Duration(before-offset) * time.Second); first.Day() == day {
+			if h, m, s := first.Clock(); h == hour && m == min && s == sec {
+				return first, true
+			}
+		}
+	}
+
+	return t, true
+}
+
+func shift(start time.Time, days int) time.Time {
+	date := time.Date(start.Year(), start.Month(), start.Day()+days, 0, 0, 0, 0, time.UTC)
+	t, _ := at(start, date.Year(), date.Month(), date.Day())
+	return t
+}
+
+func (r *Rule) occurrences(start time.Time, period int) []time.Time {
+	var result []time.Time
+
+	switch r.Frequency {
+	case Daily:
+		result = append(result, shift(start, period))
+	case Weekly:
+		days := r.sortedDays()
+		if len(days) == 0 {
+			days = []time.Weekday{start.Weekday()}
+		}
+
+		monday := period*7 - int(start.Weekday()+6)%7
+		for _, day := range days {
+			result = append(result, shift(start, monday+int(day+6)%7))
+		}
+	case Monthly:
+		first := time.Date(start.Year(), start.Month()+time.Month(period), 1, 0, 0, 0, 0, time.UTC)
+		last := first.AddDate(0, 1, -1).Day()
+
+		days := r.ByMonthDay
+		if len(days) == 0 {
+			days = []int{start.Day()}
+		}
+
+		var monthDays []int
+		for _, day := range days {
+			if day < 0 {
+				day = last + day + 1
+			}
+			if day >= 1 && day <= last {
+				monthDays = append(monthDays, day)
+			}
+		}
+		sort.Ints(monthDays)
+
+		for i, day := range monthDays {
+			if i > 0 && monthDays[i-1] == day {
+				continue
+			}
+			if t, ok := at(start, first.Year(), first.Month(), day); ok {
+				result = append(result, t)
+			}
+		}
+	case Yearly:
+		if t, ok := at(start, start.Year()+period, start.Month(), start.Day()); ok {
+			result = append(result, t)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,229 @@
+package rrule
+
+import (
+	"errors"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+func mustTime(t *testing.T, loc *time.Location, value string) time.Time {
+	t.Helper()
+	tm, err := time.ParseInLocation("2006-01-02 15:04", value, loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tm
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		rule string
+		want string
+	}{
+		{"daily", "FREQ=DAILY", "FREQ=DAILY"},
+		{"default interval dropped", "FREQ=DAILY;INTERVAL=1", "FREQ=DAILY"},
+		{"prefix and case", "rrule:freq=weekly;interval=2", "FREQ=WEEKLY;INTERVAL=2"},
+		{"weekdays ordered from monday", "FREQ=WEEKLY;BYDAY=SU,FR,MO", "FREQ=WEEKLY;BYDAY=MO,FR,SU"},
+		{"month days", "FREQ=MONTHLY;BYMONTHDAY=1,-1", "FREQ=MONTHLY;BYMONTHDAY=1,-1"},
+		{"count", "FREQ=DAILY;COUNT=10", "FREQ=DAILY;COUNT=10"},
+		{"until", "FREQ=YEARLY;UNTIL=20301231", "FREQ=YEARLY;UNTIL=20301231"},
+		{"parts in any order", "UNTIL=20301231;BYDAY=TU;FREQ=WEEKLY", "FREQ=WEEKLY;BYDAY=TU;UNTIL=20301231"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse(%q) error: %v", tt.rule, err)
+			}
+
+			if got := rule.String(); got != tt.want {
+				t.Fatalf("Parse(%q).String() = %q, want %q", tt.rule, got, tt.want)
+			}
+
+			again, err := Parse(rule.String())
+			if err != nil {
+				t.Fatalf("Parse(%q) error: %v", rule.String(), err)
+			}
+			if again.String() != rule.String() {
+				t.Fatalf("round trip of %q gives %q", rule.String(), again.String())
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		rule string
+	}{
+		{"empty", ""},
+		{"prefix only", "RRULE:"},
+		{"no frequency", "INTERVAL=2"},
+		{"unsupported frequency", "FREQ=HOURLY"},
+		{"unsupported part", "FREQ=DAILY;BYHOUR=9"},
+		{"unsupported set position", "FREQ=MONTHLY;BYSETPOS=-1"},
+		{"malformed part", "FREQ=DAILY;"},
+		{"missing value", "FREQ"},
+		{"duplicated part", "FREQ=DAILY;FREQ=WEEKLY"},
+		{"zero interval", "FREQ=DAILY;INTERVAL=0"},
+		{"large interval", "FREQ=DAILY;INTERVAL=1000"},
+		{"unknown weekday", "FREQ=WEEKLY;BYDAY=XX"},
+		{"ordinal weekday", "FREQ=WEEKLY;BYDAY=1MO"},
+		{"weekdays of daily rule", "FREQ=DAILY;BYDAY=MO"},
+		{"month days of weekly rule", "FREQ=WEEKLY;BYMONTHDAY=1"},
+		{"zero month day", "FREQ=MONTHLY;BYMONTHDAY=0"},
+		{"month day out of range", "FREQ=MONTHLY;BYMONTHDAY=32"},
+		{"until with dashes", "FREQ=DAILY;UNTIL=2024-05-01"},
+		{"until with time", "FREQ=DAILY;UNTIL=20240501T000000Z"},
+		{"zero count", "FREQ=DAILY;COUNT=0"},
+		{"count and until", "FREQ=DAILY;COUNT=3;UNTIL=20240501"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.rule); !errors.Is(err, ErrInvalid) {
+				t.Fatalf("Parse(%q) error = %v, want ErrInvalid", tt.rule, err)
+			}
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	newYork := mustLoad(t, "America/New_York")
+
+	tests := []struct {
+		name  string
+		rule  string
+		loc   *time.Location
+		start string
+		after string
+		// want is empty when the series ends.
+		want string
+	}{
+		{"daily", "FREQ=DAILY", time.UTC, "2024-05-01 09:00", "2024-05-01 09:00", "2024-05-02 09:00"},
+		{"start itself", "FREQ=DAILY", time.UTC, "2024-05-01 09:00", "2024-04-01 00:00", "2024-05-01 09:00"},
+		{"every 3 days", "FREQ=DAILY;INTERVAL=3", time.UTC, "2024-05-01 09:00", "2024-05-05 00:00", "2024-05-07 09:00"},
+		{"distant start", "FREQ=DAILY", time.UTC, "2020-01-01 09:00", "2024-05-01 12:00", "2024-05-02 09:00"},
+
+		{"monthly from the 31st skips short months", "FREQ=MONTHLY", time.UTC, "2024-01-31 09:00", "2024-01-31 09:00", "2024-03-31 09:00"},
+		{"monthly from the 31st skips april", "FREQ=MONTHLY", time.UTC, "2024-01-31 09:00", "2024-03-31 09:00", "2024-05-31 09:00"},
+		{"monthly from the 30th skips february", "FREQ=MONTHLY", time.UTC, "2024-01-30 09:00", "2024-01-30 09:00", "2024-03-30 09:00"},
+		{"last day of february in a leap year", "FREQ=MONTHLY;BYMONTHDAY=-1", time.UTC, "2024-01-31 09:00", "2024-01-31 09:00", "2024-02-29 09:00"},
+		{"last day of february", "FREQ=MONTHLY;BYMONTHDAY=-1", time.UTC, "2023-01-31 09:00", "2023-01-31 09:00", "2023-02-28 09:00"},
+		{"last day of april", "FREQ=MONTHLY;BYMONTHDAY=-1", time.UTC, "2024-03-31 09:00", "2024-03-31 09:00", "2024-04-30 09:00"},
+
+		{"yearly from february 29th", "FREQ=YEARLY", time.UTC, "2024-02-29 09:00", "2024-02-29 09:00", "2028-02-29 09:00"},
+		{"yearly from february 29th over a century", "FREQ=YEARLY", time.UTC, "2096-02-29 09:00", "2096-02-29 09:00", "2104-02-29 09:00"},
+		{"every third year from february 29th", "FREQ=YEARLY;INTERVAL=3", time.UTC, "2024-02-29 09:00", "2024-02-29 09:00", "2036-02-29 09:00"},
+
+		{"weekly on chosen days", "FREQ=WEEKLY;BYDAY=MO,FR", time.UTC, "2024-05-06 09:00", "2024-05-06 09:00", "2024-05-10 09:00"},
+		{"weekly on chosen days next week", "FREQ=WEEKLY;BYDAY=MO,FR", time.UTC, "2024-05-06 09:00", "2024-05-10 09:00", "2024-05-13 09:00"},
+		{"weekly starting off the chosen days", "FREQ=WEEKLY;BYDAY=MO", time.UTC, "2024-05-08 09:00", "2024-05-08 09:00", "2024-05-13 09:00"},
+		{"every other week", "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,TH", time.UTC, "2024-05-07 09:00", "2024-05-09 09:00", "2024-05-21 09:00"},
+		{"month days", "FREQ=MONTHLY;BYMONTHDAY=1,15", time.UTC, "2024-05-01 09:00", "2024-05-01 09:00", "2024-05-15 09:00"},
+		{"month days next month", "FREQ=MONTHLY;BYMONTHDAY=1,15", time.UTC, "2024-05-01 09:00", "2024-05-15 09:00", "2024-06-01 09:00"},
+		{"first and last month day", "FREQ=MONTHLY;BYMONTHDAY=1,-1", time.UTC, "2024-02-01 09:00", "2024-02-01 09:00", "2024-02-29 09:00"},
+		{"same month day twice", "FREQ=MONTHLY;BYMONTHDAY=30,-1", time.UTC, "2024-04-30 09:00", "2024-04-30 09:00", "2024-05-30 09:00"},
+
+		{"until includes its day", "FREQ=DAILY;UNTIL=20240503", time.UTC, "2024-05-01 09:00", "2024-05-02 09:00", "2024-05-03 09:00"},
+		{"until ends the series", "FREQ=DAILY;UNTIL=20240503", time.UTC, "2024-05-01 09:00", "2024-05-03 09:00", ""},
+		{"until before the next occurrence", "FREQ=MONTHLY;UNTIL=20240330", time.UTC, "2024-01-31 09:00", "2024-01-31 09:00", ""},
+		{"count", "FREQ=DAILY;COUNT=3", time.UTC, "2024-05-01 09:00", "2024-05-02 09:00", "2024-05-03 09:00"},
+		{"count ends the series", "FREQ=DAILY;COUNT=3", time.UTC, "2024-05-01 09:00", "2024-05-03 09:00", ""},
+		{"count of one", "FREQ=WEEKLY;COUNT=1", time.UTC, "2024-05-01 09:00", "2024-04-01 00:00", "2024-05-01 09:00"},
+		{"count of one ends at start", "FREQ=WEEKLY;COUNT=1", time.UTC, "2024-05-01 09:00", "2024-05-01 09:00", ""},
+		{"count includes start", "FREQ=WEEKLY;BYDAY=MO;COUNT=2", time.UTC, "2024-05-08 09:00", "2024-05-08 09:00", "2024-05-13 09:00"},
+		{"count includes start to the end", "FREQ=WEEKLY;BYDAY=MO;COUNT=2", time.UTC, "2024-05-08 09:00", "2024-05-13 09:00", ""},
+		{"count skips missing days", "FREQ=MONTHLY;COUNT=2", time.UTC, "2024-01-31 09:00", "2024-01-31 09:00", "2024-03-31 09:00"},
+
+		// 2:30 doesn't exist on March 10th, 2024 in New York and is read with
+		// the offset before the change.
+		{"spring forward gap", "FREQ=DAILY", newYork, "2024-03-09 02:30", "2024-03-09 02:30", "2024-03-10 03:30"},
+		{"after spring forward", "FREQ=DAILY", newYork, "2024-03-09 02:30", "2024-03-10 12:00", "2024-03-11 02:30"},
+		{"wall clock across spring forward", "FREQ=WEEKLY", newYork, "2024-03-05 09:00", "2024-03-05 09:00", "2024-03-12 09:00"},
+		{"monthly into the spring forward gap", "FREQ=MONTHLY", newYork, "2024-02-10 02:30", "2024-02-10 02:30", "2024-03-10 03:30"},
+		// 1:30 happens twice on November 3rd, 2024 in New York, the first
+		// one is taken.
+		{"fall back repeat", "FREQ=DAILY", newYork, "2024-11-02 01:30", "2024-11-02 01:30", "2024-11-03 01:30 EDT"},
+		{"after fall back", "FREQ=DAILY", newYork, "2024-11-02 01:30", "2024-11-03 12:00", "2024-11-04 01:30"},
+		{"wall clock across fall back", "FREQ=WEEKLY", newYork, "2024-10-29 09:00", "2024-10-29 09:00", "2024-11-05 09:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse(%q) error: %v", tt.rule, err)
+			}
+
+			start := mustTime(t, tt.loc, tt.start)
+			got, ok := rule.Next(start, mustTime(t, tt.loc, tt.after))
+			if tt.want == "" {
+				if ok {
+					t.Fatalf("Next() = %v, want the series to end", got)
+				}
+				return
+			}
+
+			if !ok {
+				t.Fatalf("Next() ended the series, want %s", tt.want)
+			}
+
+			var want time.Time
+			if len(tt.want) > len("2006-01-02 15:04") {
+				want, err = time.ParseInLocation("2006-01-02 15:04 MST", tt.want, tt.loc)
+				if err != nil {
+					t.Fatal(err)
+				}
+			} else {
+				want = mustTime(t, tt.loc, tt.want)
+			}
+
+			if !got.Equal(want) {
+				t.Fatalf("Next() = %v, want %v", got.In(tt.loc), want)
+			}
+		})
+	}
+}
+
+func TestRest(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  string
+		start string
+		next  string
+		want  string
+	}{
+		{"uncounted", "FREQ=DAILY", "2024-05-01 09:00", "2024-05-04 09:00", "FREQ=DAILY"},
+		{"following occurrence", "FREQ=DAILY;COUNT=5", "2024-05-01 09:00", "2024-05-02 09:00", "FREQ=DAILY;COUNT=4"},
+		{"skipped occurrences", "FREQ=DAILY;COUNT=5", "2024-05-01 09:00", "2024-05-04 09:00", "FREQ=DAILY;COUNT=2"},
+		{"start off the rule", "FREQ=WEEKLY;BYDAY=MO;COUNT=3", "2024-05-08 09:00", "2024-05-13 09:00", "FREQ=WEEKLY;BYDAY=MO;COUNT=2"},
+		{"missing month days", "FREQ=MONTHLY;COUNT=3", "2024-01-31 09:00", "2024-03-31 09:00", "FREQ=MONTHLY;COUNT=2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse(%q) error: %v", tt.rule, err)
+			}
+
+			rest := rule.Rest(mustTime(t, time.UTC, tt.start), mustTime(t, time.UTC, tt.next))
+			if got := rest.String(); got != tt.want {
+				t.Fatalf("Rest() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}